@@ -0,0 +1,156 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/control"
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+// Control handles a control request, it must be called from xwm.HandleEvent.
+func (w *wall) Control(req control.Request) control.Response {
+	switch req.Command {
+	case control.CommandStatus:
+		status := w.status()
+		return control.Response{Status: &status}
+	case control.CommandFullscreen:
+		manager, index, err := w.window(req.Window, req.Output)
+		if err != nil {
+			return control.Response{Error: err.Error()}
+		}
+
+		manager.Do(w.x, xwm.Action{Name: xwm.ActionFullscreen, Arg: strconv.Itoa(index + 1)})
+	case control.CommandReload:
+		manager, index, err := w.window(req.Window, req.Output)
+		if err != nil {
+			return control.Response{Error: err.Error()}
+		}
+
+		manager.Reload(index)
+	case control.CommandLayout, control.CommandNext, control.CommandPrevious, control.CommandMute, control.CommandUnmute, control.CommandPage:
+		managers, err := w.outputManagers(req.Output)
+		if err != nil {
+			return control.Response{Error: err.Error()}
+		}
+
+		var pageAction xwm.Action
+		if req.Command == control.CommandPage {
+			pageAction, err = xwm.ParseAction(xwm.ActionPage + ":" + req.Page)
+			if err != nil {
+				return control.Response{Error: err.Error()}
+			}
+		}
+
+		for _, wm := range managers {
+			switch req.Command {
+			case control.CommandLayout:
+				if req.Layout == "" {
+					wm.manager.Do(w.x, xwm.Action{Name: xwm.ActionLayout})
+				} else if err := w.setLayout(wm, req.Layout); err != nil {
+					return control.Response{Error: err.Error()}
+				}
+			case control.CommandNext:
+				wm.manager.Do(w.x, xwm.Action{Name: xwm.ActionNext})
+			case control.CommandPrevious:
+				wm.manager.Do(w.x, xwm.Action{Name: xwm.ActionPrevious})
+			case control.CommandMute:
+				wm.manager.SetMute(true)
+			case control.CommandUnmute:
+				wm.manager.SetMute(false)
+			case control.CommandPage:
+				wm.manager.Do(w.x, pageAction)
+			}
+		}
+	default:
+		return control.Response{Error: fmt.Sprintf("%s: unknown command", req.Command)}
+	}
+
+	return control.Response{}
+}
+
+func (w *wall) status() control.Status {
+	status := control.Status{Outputs: []control.OutputStatus{}}
+	for _, wm := range w.managers {
+		fullscreen := wm.manager.Fullscreen()
+		focus := wm.manager.Focus()
+
+		windows := []control.WindowStatus{}
+		for i, window := range wm.manager.Windows() {
+			var errString string
+			if err := window.Err(); err != nil {
+				errString = err.Error()
+			}
+
+			playerStatus := window.Status()
+			var lastFrame string
+			if !playerStatus.LastFrame.IsZero() {
+				lastFrame = playerStatus.LastFrame.Format(time.RFC3339)
+			}
+
+			windows = append(windows, control.WindowStatus{
+				Number:     i + 1,
+				Name:       window.Name(),
+				Fullscreen: i == fullscreen,
+				Focused:    i == focus,
+				Error:      errString,
+				State:      playerStatus.State,
+				Reason:     playerStatus.Reason,
+				LastFrame:  lastFrame,
+				Fallback:   playerStatus.Fallback,
+			})
+		}
+
+		status.Outputs = append(status.Outputs, control.OutputStatus{
+			Name:    wm.manager.Output().Name,
+			Layout:  wm.layout,
+			Muted:   wm.manager.Muted(),
+			Page:    wm.manager.Page() + 1,
+			Pages:   wm.manager.Pages(),
+			Windows: windows,
+		})
+	}
+
+	return status
+}
+
+// window finds the manager and index of a window by name or by number on the output, the same number as key bindings.
+// Numbers need an output when there is more than one output.
+func (w *wall) window(ref, output string) (*xwm.Manager, int, error) {
+	managers, err := w.outputManagers(output)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	number, err := strconv.Atoi(ref)
+	if err == nil && len(managers) > 1 {
+		return nil, 0, fmt.Errorf("%s: window number needs an output when there is more than one output", ref)
+	}
+
+	for _, wm := range managers {
+		for i, wc := range wm.windowConfigs {
+			if (err == nil && i+1 == number) || (err != nil && wc.Window.Name == ref) {
+				return wm.manager, i, nil
+			}
+		}
+	}
+
+	return nil, 0, fmt.Errorf("%s: window not found", ref)
+}
+
+// outputManagers returns the manager of the output or all managers when output is empty.
+func (w *wall) outputManagers(output string) ([]*wallManager, error) {
+	var managers []*wallManager
+	for _, wm := range w.managers {
+		if output == "" || wm.manager.Output().Name == output {
+			managers = append(managers, wm)
+		}
+	}
+
+	if output != "" && len(managers) == 0 {
+		return nil, fmt.Errorf("%s: output not found", output)
+	}
+
+	return managers, nil
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+// entry accumulates digits into a window number that is shown in a prompt until it is confirmed or cancelled.
+// Window 0 is layout view.
+// All methods must be called from xwm.HandleEvent.
+type entry struct {
+	q       *xwm.Queue
+	w       *wall
+	timeout time.Duration
+	wm      *wallManager
+	digits  string
+	timer   *time.Timer
+	// gen invalidates timers that already fired before they were stopped
+	gen int
+}
+
+func newEntry(q *xwm.Queue, w *wall, timeout time.Duration) *entry {
+	return &entry{
+		q:       q,
+		w:       w,
+		timeout: timeout,
+	}
+}
+
+// Digit adds the digit to the window number of the manager.
+// The number is confirmed right away when another digit would not make a valid window number.
+func (e *entry) Digit(wm *wallManager, digit string) {
+	if e.wm != wm {
+		e.Cancel()
+		e.wm = wm
+	}
+
+	e.digits += digit
+	number, err := strconv.Atoi(e.digits)
+	if err != nil {
+		e.Cancel()
+		return
+	}
+
+	// Confirm when appending a digit cannot give a window that Confirm would show
+	if _, ok := wm.window(number * 10); number == 0 || !ok {
+		e.Confirm()
+		return
+	}
+
+	wm.manager.ShowPrompt(e.w.x, e.digits+"_")
+	e.schedule()
+}
+
+// Confirm shows the window in fullscreen view.
+func (e *entry) Confirm() {
+	wm, digits := e.wm, e.digits
+	e.Cancel()
+	if wm == nil || digits == "" {
+		return
+	}
+
+	number, err := strconv.Atoi(digits)
+	if err != nil {
+		return
+	}
+
+	log.Printf("app.entry.Confirm: output %q: %d", wm.manager.Output().Name, number)
+	if number == 0 {
+		e.w.do(wm, xwm.Action{Name: xwm.ActionLayout})
+		return
+	}
+
+	showAction(e.w, wm, xwm.Action{Name: xwm.ActionFullscreen, Arg: digits})
+}
+
+// Cancel clears the window number and hides the prompt.
+func (e *entry) Cancel() {
+	e.gen++
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+
+	if e.wm != nil {
+		e.wm.manager.HidePrompt(e.w.x)
+	}
+	e.wm = nil
+	e.digits = ""
+}
+
+func (e *entry) schedule() {
+	e.gen++
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+
+	gen := e.gen
+	e.timer = e.q.AfterFunc(e.timeout, func() {
+		if gen != e.gen {
+			return
+		}
+
+		e.Confirm()
+	})
+}
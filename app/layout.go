@@ -0,0 +1,131 @@
+package app
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/config"
+	"github.com/ItsNotGoodName/x-ipcviewer/mosaic"
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+func newLayout(lc config.LayoutConfig, count int) mosaic.Layout {
+	var layout mosaic.Layout
+	switch {
+	case lc.Layout.IsManual():
+		layout = mosaic.NewLayoutManual(lc.LayoutManualWindows)
+	case lc.Layout.IsGrid():
+		layout = mosaic.NewLayoutGrid(lc.Columns, lc.Rows)
+	case lc.Layout.IsSpan():
+		layout = mosaic.NewLayoutSpan(lc.Columns, lc.Rows, lc.LayoutSpan)
+	case lc.Layout.IsPreset():
+		layout, _ = mosaic.NewLayoutPreset(string(lc.Layout))
+	default:
+		layout = mosaic.NewLayoutAuto(count, float64(lc.AspectRatioValue), lc.Letterbox)
+	}
+
+	if lc.Gap == 0 && lc.BorderWidth == 0 {
+		return layout
+	}
+
+	return mosaic.NewLayoutGap(layout, uint16(lc.Gap), uint16(lc.BorderWidth))
+}
+
+func newStyle(cfg *config.Config, lc config.LayoutConfig) xwm.Style {
+	return xwm.Style{
+		Background:       lc.BackgroundColorValue,
+		BorderWidth:      uint16(lc.BorderWidth),
+		BorderColor:      lc.BorderColorValue,
+		FocusBorderWidth: uint16(cfg.FocusBorderWidth),
+		FocusColor:       cfg.FocusColorValue,
+		Label: xwm.Label{
+			Hidden:   !cfg.Label.Show,
+			Clock:    cfg.Label.Clock,
+			Position: cfg.Label.Position,
+			FontSize: cfg.Label.FontSize,
+		},
+	}
+}
+
+// layoutConfig returns the layout config of the manager.
+// The manager's named layout is used when it still exists, otherwise the output's default layout is used.
+func (w *wall) layoutConfig(wm *wallManager) config.LayoutConfig {
+	if wm.layout != "" {
+		if lc, ok := w.cfg.NamedLayout(wm.layout); ok {
+			return lc
+		}
+	}
+
+	name, lc := w.cfg.OutputLayout(wm.manager.Output().Name)
+	wm.layout = name
+
+	return lc
+}
+
+// setLayout switches the manager to the named layout and activates layout view.
+// Name can also be next or previous to cycle through named layouts.
+func (w *wall) setLayout(wm *wallManager, name string) error {
+	if name == xwm.ArgNext || name == xwm.ArgPrevious {
+		names := w.cfg.LayoutNames()
+		if len(names) == 0 {
+			return nil
+		}
+
+		i := -1
+		for j := range names {
+			if names[j] == wm.layout {
+				i = j
+				break
+			}
+		}
+
+		if name == xwm.ArgNext {
+			i = (i + 1) % len(names)
+		} else if i <= 0 {
+			i = len(names) - 1
+		} else {
+			i--
+		}
+
+		name = names[i]
+	}
+
+	lc, ok := w.cfg.NamedLayout(name)
+	if !ok {
+		return fmt.Errorf("%s: unknown layout", name)
+	}
+
+	log.Printf("app.wall.setLayout: output %q: %s", wm.manager.Output().Name, name)
+
+	wm.layout = name
+	wm.manager.SetFullscreen(w.x, 0)
+	wm.manager.SetStyle(w.x, newStyle(w.cfg, lc))
+	wm.manager.SetLayout(w.x, newLayout(lc, len(wm.manager.Windows())))
+
+	return nil
+}
+
+// do the action on the manager.
+// Switching layouts and entering window numbers are handled here because the Manager does not know about named layouts or timers.
+func (w *wall) do(wm *wallManager, action xwm.Action) {
+	switch action.Name {
+	case xwm.ActionDigit:
+		w.entry.Digit(wm, action.Arg)
+		return
+	case xwm.ActionEnter:
+		w.entry.Confirm()
+		return
+	case xwm.ActionCancel:
+		w.entry.Cancel()
+		return
+	}
+
+	if action.Name == xwm.ActionLayout && action.Arg != "" {
+		if err := w.setLayout(wm, action.Arg); err != nil {
+			log.Println("app.wall.do:", err)
+		}
+		return
+	}
+
+	wm.manager.Do(w.x, action)
+}
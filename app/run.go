@@ -1,12 +1,13 @@
 package app
 
 import (
-	"math"
+	"log"
+	"os"
 	"sync"
 
 	"github.com/ItsNotGoodName/x-ipcviewer/closer"
 	"github.com/ItsNotGoodName/x-ipcviewer/config"
-	"github.com/ItsNotGoodName/x-ipcviewer/mosaic"
+	"github.com/ItsNotGoodName/x-ipcviewer/control"
 	"github.com/ItsNotGoodName/x-ipcviewer/mpv"
 	"github.com/ItsNotGoodName/x-ipcviewer/xcursor"
 	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
@@ -27,87 +28,150 @@ func Run(cfg *config.Config) error {
 		return nil
 	})
 
-	if cfg.ConfigWatchExit {
-		viper.WatchConfig()
-		viper.OnConfigChange(func(in fsnotify.Event) {
-			x.Close()
-		})
-	}
-
 	// Cursor
 	cursor, err := xcursor.CreateCursor(x, xcursor.LeftPtr)
 	if err != nil {
 		return err
 	}
 
-	// Layout
-	var layout mosaic.Layout
-	if cfg.Layout.IsAuto() {
-		layout = mosaic.NewLayoutGridCount(len(cfg.Windows))
-	} else {
-		layout = mosaic.NewLayoutManual(cfg.LayoutManualWindows)
+	// Outputs
+	screen := xproto.Setup(x).DefaultScreen(x)
+	if err := xwm.InitOutputs(x, screen); err != nil {
+		log.Println("app.Run: outputs:", err)
 	}
 
-	// Manager
-	manager, err := xwm.NewManager(x, xproto.Setup(x).DefaultScreen(x), cursor, mosaic.New(layout))
-	if err != nil {
+	q := xwm.NewQueue()
+
+	// Wall
+	w := newWall(x, screen, cursor, cfg, q)
+	defer w.Release()
+
+	// Key bindings
+	if err := w.UpdateKeymap(); err != nil {
 		return err
 	}
-	defer manager.Release()
 
-	// Create windows
-	windows, err := createWindows(cfg, x, manager.WID(), layout)
+	// Managers
+	if err := w.Sync(); err != nil {
+		return err
+	}
+
+	// Config
+	if viper.ConfigFileUsed() != "" {
+		viper.OnConfigChange(func(in fsnotify.Event) {
+			if cfg.ConfigWatchExit {
+				x.Close()
+				return
+			}
+
+			log.Println("app.Run: reloading config:", in.Name)
+			reload(q, w)
+		})
+		viper.WatchConfig()
+	}
+
+	// Reload on SIGHUP
+	defer reloadOnSignal(q, w)()
+
+	// Control
+	controlPath := cfg.ControlSocket
+	if controlPath == "" {
+		controlPath = control.SocketPath(os.Getenv("DISPLAY"))
+	}
+	server, err := control.Listen(controlPath, func(req control.Request) control.Response {
+		var res control.Response
+		if !q.Do(func() { res = w.Control(req) }) {
+			return control.Response{Error: "exiting"}
+		}
+		return res
+	})
 	if err != nil {
 		return err
 	}
+	defer server.Close()
+	closer.Add(server.Close)
+	go server.Serve()
 
-	// Add windows
-	manager.AddWindows(x, windows)
+	// Tour
+	w.tour.Start()
 
 	// Events
-	xwm.HandleEvent(x, manager)
+	xwm.HandleEvent(x, w, q)
 
 	return nil
 }
 
-func createWindows(cfg *config.Config, x *xgb.Conn, root xproto.Window, layout mosaic.Layout) ([]xwm.Window, error) {
-	count := int(math.Min(float64(layout.Count()), float64(len(cfg.Windows))))
+var reloadMu sync.Mutex
 
-	windows := make([]xwm.Window, count)
-	wg := sync.WaitGroup{}
-	errC := make(chan error, count)
-	for i := 0; i < count; i++ {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
+// reload reads and parses the config and reloads the wall in place.
+func reload(q *xwm.Queue, w *wall) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
 
-			// Create X window
-			w, err := xwm.CreateXSubWindow(x, root)
-			if err != nil {
-				errC <- err
-				return
+	if err := viper.ReadInConfig(); err != nil {
+		log.Println("app.reload:", err)
+		return
+	}
+
+	var cfg config.Config
+	if err := config.Parse(&cfg); err != nil {
+		log.Println("app.reload:", err)
+		return
+	}
+
+	q.Do(func() {
+		if err := w.Reload(&cfg); err != nil {
+			log.Println("app.reload:", err)
+		}
+	})
+}
+
+// createWindows creates X windows with players that are started in the background.
+func createWindows(q *xwm.Queue, x *xgb.Conn, root xproto.Window, style xwm.Style, windowConfigs []windowConfig) ([]xwm.Window, error) {
+	windows := make([]xwm.Window, 0, len(windowConfigs))
+	for _, wc := range windowConfigs {
+		// Create X window
+		w, err := xwm.CreateXSubWindow(x, root, style)
+		if err != nil {
+			for _, window := range windows {
+				window.Release(x)
 			}
+			return nil, err
+		}
 
-			// Crate player factory
-			pf := mpv.NewPlayerFactory(cfg.Windows[i].Name, cfg.Windows[i].Flags, cfg.Player.GPU, cfg.Windows[i].LowLatency)
+		// Crate player factory
+		fallbacks := map[string][]string{wc.Window.Main: wc.Window.MainFallbacks}
+		if wc.Window.Sub != "" && wc.Window.Sub != wc.Window.Main {
+			fallbacks[wc.Window.Sub] = wc.Window.SubFallbacks
+		}
+		pf := mpv.NewPlayerFactory(wc.Window.Name, wc.Window.Flags, wc.GPU, wc.Window.LowLatency, wc.Window.Reconnect, fallbacks)
 
-			// Create player
-			p, err := pf(w)
+		// Create player
+		if wc.Window.Sub == "" || wc.Window.Sub == wc.Window.Main {
+			p := xwm.NewPlayerCache(xwm.NewRetryPlayer(wc.Window.Name, w, pf))
+
+			// Create window
+			windows = append(windows, xwm.NewWindow(w, wc.Window.Name, p, wc.Window.Main, wc.Window.Sub, wc.Background))
+			continue
+		}
+
+		// Create switch player that keeps showing the old stream until the new stream starts
+		var layers [2]xproto.Window
+		for i := range layers {
+			layers[i], err = xwm.CreateXChildWindow(x, w)
 			if err != nil {
-				errC <- err
-				return
+				xproto.DestroyWindow(x, w)
+				for _, window := range windows {
+					window.Release(x)
+				}
+				return nil, err
 			}
-			p = xwm.NewPlayerCache(p)
+		}
+		p := xwm.NewPlayerCache(xwm.NewSwitchPlayer(q, x, wc.Window.Name, layers, pf))
 
-			// Create window
-			windows[i] = xwm.NewWindow(w, p, cfg.Windows[i].Main, cfg.Windows[i].Sub, cfg.Background)
-		}(i)
-	}
-	wg.Wait()
-	select {
-	case err := <-errC:
-		return nil, err
-	default:
-		return windows, nil
+		// Create window
+		windows = append(windows, xwm.NewWindow(w, wc.Window.Name, p, wc.Window.Main, wc.Window.Sub, wc.Background, layers[:]...))
 	}
+
+	return windows, nil
 }
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+// reloadOnSignal reloads the config when SIGHUP is received until stop is called.
+func reloadOnSignal(q *xwm.Queue, w *wall) (stop func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	doneC := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				log.Println("app.reloadOnSignal: reloading config")
+				reload(q, w)
+			case <-doneC:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(c)
+		close(doneC)
+	}
+}
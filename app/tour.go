@@ -0,0 +1,109 @@
+package app
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/config"
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+// tour cycles through views on a timer and pauses on user input.
+// All methods must be called from xwm.HandleEvent.
+type tour struct {
+	q     *xwm.Queue
+	w     *wall
+	cfg   config.Tour
+	step  int
+	timer *time.Timer
+	// gen invalidates timers that already fired before they were stopped
+	gen int
+}
+
+func newTour(q *xwm.Queue, w *wall, cfg config.Tour) *tour {
+	return &tour{
+		q:   q,
+		w:   w,
+		cfg: cfg,
+	}
+}
+
+// Start the tour from the first step.
+func (t *tour) Start() {
+	if len(t.cfg.Steps) == 0 {
+		return
+	}
+
+	t.step = 0
+	t.show()
+}
+
+// Stop the tour.
+func (t *tour) Stop() {
+	t.gen++
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
+
+// Pause the tour and resume it with the next step when there is no user input for the idle duration.
+func (t *tour) Pause() {
+	if len(t.cfg.Steps) == 0 {
+		return
+	}
+
+	t.schedule(t.cfg.Idle, func() {
+		log.Println("app.tour.Pause: resuming")
+		t.show()
+	})
+}
+
+func (t *tour) schedule(d time.Duration, fn func()) {
+	t.Stop()
+
+	gen := t.gen
+	t.timer = t.q.AfterFunc(d, func() {
+		if gen != t.gen {
+			return
+		}
+
+		fn()
+	})
+}
+
+// show the current step and schedule the next step.
+func (t *tour) show() {
+	step := t.cfg.Steps[t.step]
+	t.step = (t.step + 1) % len(t.cfg.Steps)
+
+	log.Println("app.tour.show:", step.Action)
+	managers, err := t.w.outputManagers(t.cfg.Output)
+	if err != nil {
+		log.Println("app.tour.show:", err)
+	}
+	for _, wm := range managers {
+		showAction(t.w, wm, step.Action)
+	}
+
+	t.schedule(step.Duration, t.show)
+}
+
+// showAction does the action without toggling the current view.
+func showAction(w *wall, wm *wallManager, action xwm.Action) {
+	manager := wm.manager
+	switch action.Name {
+	case xwm.ActionFullscreen:
+		i, err := strconv.Atoi(action.Arg)
+		if err != nil {
+			return
+		}
+
+		if window, ok := wm.window(i); ok {
+			manager.SetFullscreen(w.x, window.WID())
+		}
+	default:
+		w.do(wm, action)
+	}
+}
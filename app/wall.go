@@ -0,0 +1,322 @@
+package app
+
+import (
+	"log"
+	"reflect"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/config"
+	"github.com/ItsNotGoodName/x-ipcviewer/mosaic"
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// wall has a Manager for each output and routes X events to them.
+type wall struct {
+	x        *xgb.Conn
+	screen   *xproto.ScreenInfo
+	cursor   xproto.Cursor
+	cfg      *config.Config
+	q        *xwm.Queue
+	keymap   xwm.Keymap
+	tour     *tour
+	entry    *entry
+	managers []*wallManager
+}
+
+type wallManager struct {
+	manager *xwm.Manager
+	// windowConfigs that created the manager's windows
+	windowConfigs []windowConfig
+	// indexes of windowConfigs in config.Config.Windows
+	indexes []int
+	// layout is the name of the named layout or empty
+	layout string
+}
+
+// windowConfig is everything a xwm.Window is created from.
+type windowConfig struct {
+	Window     config.Window
+	GPU        string
+	Background bool
+}
+
+func newWall(x *xgb.Conn, screen *xproto.ScreenInfo, cursor xproto.Cursor, cfg *config.Config, q *xwm.Queue) *wall {
+	w := &wall{
+		x:      x,
+		screen: screen,
+		cursor: cursor,
+		cfg:    cfg,
+		q:      q,
+	}
+	w.tour = newTour(q, w, cfg.Tour)
+	w.entry = newEntry(q, w, cfg.EntryTimeout)
+
+	return w
+}
+
+// Sync creates, updates, and releases managers so there is one for each connected output that has windows.
+func (w *wall) Sync() error {
+	outputs := xwm.Outputs(w.x, w.screen)
+
+	var connected []string
+	for _, output := range outputs {
+		connected = append(connected, output.Name)
+	}
+
+	var managers []*wallManager
+	var retErr error
+	for _, output := range outputs {
+		indexes := w.cfg.OutputWindows(output.Name, output.Primary, connected)
+
+		wm := w.take(output.Name)
+		if len(indexes) == 0 {
+			log.Printf("app.wall.Sync: output %q: no windows", output.Name)
+			if wm != nil {
+				w.releaseManager(wm)
+			}
+			continue
+		}
+
+		if wm == nil {
+			log.Printf("app.wall.Sync: output %q: creating manager", output.Name)
+			_, lc := w.cfg.OutputLayout(output.Name)
+			manager, err := xwm.NewManager(w.x, w.screen, output, w.cursor, mosaic.New(mosaic.NewLayoutGridCount(0)), newStyle(w.cfg, lc))
+			if err != nil {
+				retErr = err
+				continue
+			}
+
+			wm = &wallManager{manager: manager}
+		} else {
+			wm.manager.SetOutput(w.x, output)
+		}
+
+		if err := w.updateManager(wm, indexes); err != nil {
+			log.Printf("app.wall.Sync: output %q: %s", output.Name, err)
+			retErr = err
+			if len(wm.windowConfigs) == 0 {
+				w.releaseManager(wm)
+				continue
+			}
+		}
+
+		managers = append(managers, wm)
+	}
+
+	// Release managers of disconnected outputs
+	for _, wm := range w.managers {
+		w.releaseManager(wm)
+	}
+
+	w.managers = managers
+
+	return retErr
+}
+
+// take removes the manager of the output from the wall.
+func (w *wall) take(output string) *wallManager {
+	for i, wm := range w.managers {
+		if wm.manager.Output().Name == output {
+			w.managers = append(w.managers[:i], w.managers[i+1:]...)
+			return wm
+		}
+	}
+
+	return nil
+}
+
+func (w *wall) releaseManager(wm *wallManager) {
+	log.Printf("app.wall.releaseManager: output %q", wm.manager.Output().Name)
+	// The entry's timeout would show a window of the released manager
+	if w.entry.wm == wm {
+		w.entry.Cancel()
+	}
+	wm.manager.Release(w.x)
+}
+
+// window returns the window with the number, starting from 1, that is the same number as key bindings.
+func (wm *wallManager) window(number int) (xwm.Window, bool) {
+	windows := wm.manager.Windows()
+	if number < 1 || number > len(windows) {
+		return xwm.Window{}, false
+	}
+
+	return windows[number-1], true
+}
+
+// updateManager updates the layout and windows of the manager.
+// Windows that have the same config are kept playing, other windows are created or released.
+func (w *wall) updateManager(wm *wallManager, indexes []int) error {
+	output := wm.manager.Output().Name
+
+	// Layout
+	lc := w.layoutConfig(wm)
+	wm.manager.SetStyle(w.x, newStyle(w.cfg, lc))
+
+	windowConfigs := make([]windowConfig, len(indexes))
+	for i, index := range indexes {
+		windowConfigs[i] = windowConfig{
+			Window:     w.cfg.Windows[index],
+			GPU:        w.cfg.Player.GPU,
+			Background: w.cfg.Background,
+		}
+	}
+
+	// Reuse windows with the same config
+	oldWindows := wm.manager.Windows()
+	reused := make([]bool, len(oldWindows))
+	windows := make([]xwm.Window, len(windowConfigs))
+	var create []int
+	for i := range windowConfigs {
+		found := false
+		for j := range oldWindows {
+			if !reused[j] && reflect.DeepEqual(wm.windowConfigs[j], windowConfigs[i]) {
+				windows[i] = oldWindows[j]
+				reused[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			create = append(create, i)
+		}
+	}
+
+	// Create windows
+	createConfigs := make([]windowConfig, len(create))
+	for i, j := range create {
+		createConfigs[i] = windowConfigs[j]
+	}
+	created, err := createWindows(w.q, w.x, wm.manager.WID(), wm.manager.Style(), createConfigs)
+	if err != nil {
+		return err
+	}
+	for i, j := range create {
+		windows[j] = created[i]
+	}
+
+	wm.windowConfigs = windowConfigs
+	wm.indexes = indexes
+	wm.manager.SetWindows(w.x, windows, newLayout(lc, len(indexes)))
+
+	// Release windows that were not reused after the manager stopped using them
+	for j := range oldWindows {
+		if !reused[j] {
+			oldWindows[j].Release(w.x)
+		}
+	}
+
+	log.Printf("app.wall.updateManager: output %q: kept %d windows, created %d windows, released %d windows", output, len(windows)-len(create), len(create), len(oldWindows)-(len(windows)-len(create)))
+
+	return nil
+}
+
+// Reload replaces the config and updates managers in place.
+func (w *wall) Reload(cfg *config.Config) error {
+	w.entry.Cancel()
+	w.entry = newEntry(w.q, w, cfg.EntryTimeout)
+	w.cfg = cfg
+
+	if err := w.UpdateKeymap(); err != nil {
+		return err
+	}
+
+	if err := w.Sync(); err != nil {
+		return err
+	}
+
+	// Restart tour
+	w.tour.Stop()
+	w.tour = newTour(w.q, w, cfg.Tour)
+	w.tour.Start()
+
+	return nil
+}
+
+func (w *wall) Release() {
+	w.tour.Stop()
+	w.entry.Cancel()
+	for _, wm := range w.managers {
+		wm.manager.Release(w.x)
+	}
+	w.managers = nil
+}
+
+func (w *wall) manager(wid xproto.Window) *wallManager {
+	for _, wm := range w.managers {
+		if wm.manager.WID() == wid {
+			return wm
+		}
+	}
+
+	return nil
+}
+
+func (w *wall) ConfigureNotify(x *xgb.Conn, ev xproto.ConfigureNotifyEvent) {
+	if wm := w.manager(ev.Window); wm != nil {
+		wm.manager.ConfigureNotify(x, ev)
+	}
+}
+
+func (w *wall) ButtonPress(x *xgb.Conn, ev xproto.ButtonPressEvent) {
+	w.tour.Pause()
+
+	if wm := w.manager(ev.Event); wm != nil {
+		wm.manager.ButtonPress(x, ev)
+	}
+}
+
+// UpdateKeymap resolves key bindings using the active keyboard mapping.
+func (w *wall) UpdateKeymap() error {
+	keymap, err := xwm.NewKeymap(w.x, w.cfg.KeyBindings)
+	if err != nil {
+		return err
+	}
+
+	w.keymap = keymap
+
+	return nil
+}
+
+func (w *wall) KeyPress(x *xgb.Conn, ev xproto.KeyPressEvent) {
+	w.tour.Pause()
+
+	action, ok := w.keymap[ev.Detail]
+	if !ok {
+		return
+	}
+
+	log.Println("app.wall.KeyPress:", action)
+
+	if action.Name == xwm.ActionQuit {
+		x.Close()
+		return
+	}
+
+	if wm := w.manager(ev.Event); wm != nil {
+		w.do(wm, action)
+	}
+}
+
+func (w *wall) Expose(x *xgb.Conn, ev xproto.ExposeEvent) {
+	for _, wm := range w.managers {
+		wm.manager.Expose(x, ev)
+	}
+}
+
+func (w *wall) MappingNotify(x *xgb.Conn, ev xproto.MappingNotifyEvent) {
+	if ev.Request != xproto.MappingKeyboard {
+		return
+	}
+
+	if err := w.UpdateKeymap(); err != nil {
+		log.Println("app.wall.MappingNotify:", err)
+	}
+}
+
+func (w *wall) OutputChange(x *xgb.Conn) {
+	if err := w.Sync(); err != nil {
+		log.Println("app.wall.OutputChange:", err)
+	}
+}
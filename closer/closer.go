@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"sync"
+	"syscall"
 )
 
 var (
@@ -19,7 +20,7 @@ type Closer func() error
 func init() {
 	closers = make(map[int]Closer)
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go handle(c)
 }
 
@@ -66,7 +67,8 @@ func close(id int) error {
 }
 
 func handle(c chan os.Signal) {
-	<-c
+	sig := <-c
+	log.Println("closer.handle:", sig)
 	mu.Lock()
 	for i := 0; i <= lastId; i++ {
 		if closer, ok := closers[i]; ok {
@@ -0,0 +1,175 @@
+/*
+Copyright © 2022 ItsNotGoodName
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/control"
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+	"github.com/spf13/cobra"
+)
+
+var ctlSocket string
+var ctlJSON bool
+
+// ctlCmd represents the ctl command
+var ctlCmd = &cobra.Command{
+	Use:   "ctl",
+	Short: "Control a running instance.",
+}
+
+func newCtlCmd(command, use, short string, args cobra.PositionalArgs, field func(req *control.Request, args []string)) *cobra.Command {
+	return &cobra.Command{
+		Use:          use,
+		Short:        short,
+		Args:         args,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := control.Request{Command: command}
+			if field != nil {
+				field(&req, args)
+			}
+
+			return ctl(req)
+		},
+	}
+}
+
+func ctlWindow(req *control.Request, args []string) {
+	req.Window = args[0]
+	if len(args) > 1 {
+		req.Output = args[1]
+	}
+}
+
+func ctlOutput(req *control.Request, args []string) {
+	if len(args) > 0 {
+		req.Output = args[0]
+	}
+}
+
+func ctlPage(req *control.Request, args []string) {
+	req.Page = args[0]
+	if len(args) > 1 {
+		req.Output = args[1]
+	}
+}
+
+func ctlLayout(req *control.Request, args []string) {
+	req.Layout = args[0]
+	if len(args) > 1 {
+		req.Output = args[1]
+	}
+}
+
+func ctl(req control.Request) error {
+	socketPath := ctlSocket
+	if socketPath == "" {
+		socketPath = cfg.ControlSocket
+	}
+	if socketPath == "" {
+		var err error
+		socketPath, err = control.FindSocketPath(os.Getenv("DISPLAY"))
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := control.Dial(socketPath)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if ctlJSON {
+		return json.NewEncoder(os.Stdout).Encode(res)
+	}
+
+	if res.Status != nil {
+		for _, output := range res.Status.Outputs {
+			name := output.Name
+			if name == "" {
+				name = "screen"
+			}
+			if output.Layout != "" {
+				name += fmt.Sprintf(" (layout %s)", output.Layout)
+			}
+			name += fmt.Sprintf(" (page %d/%d)", output.Page, output.Pages)
+			if output.Muted {
+				name += " (muted)"
+			}
+			fmt.Println(name)
+
+			for _, window := range output.Windows {
+				line := fmt.Sprintf("  %d %s [%s]", window.Number, window.Name, window.State)
+				if window.Fullscreen {
+					line += " (fullscreen)"
+				}
+				if window.Focused {
+					line += " (focused)"
+				}
+				if window.Fallback != 0 {
+					line += fmt.Sprintf(" (fallback %d)", window.Fallback)
+				}
+				if window.Error != "" {
+					line += " error: " + window.Error
+				} else if window.Reason != "" {
+					line += " reason: " + window.Reason
+				}
+				if window.LastFrame != "" && window.State != xwm.StatePlaying {
+					line += " last frame: " + window.LastFrame
+				}
+				fmt.Println(line)
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(ctlCmd)
+
+	ctlCmd.PersistentFlags().StringVar(&ctlSocket, "socket", "", "control socket (default is from config, $DISPLAY, or the only socket of the user)")
+	ctlCmd.PersistentFlags().BoolVar(&ctlJSON, "json", false, "print response as JSON")
+
+	ctlCmd.AddCommand(
+		newCtlCmd(control.CommandStatus, "status", "Show outputs and windows.", cobra.NoArgs, nil),
+		newCtlCmd(control.CommandFullscreen, "fullscreen WINDOW [OUTPUT]", "Toggle fullscreen view of window by number on output or name.", cobra.RangeArgs(1, 2), ctlWindow),
+		newCtlCmd(control.CommandReload, "reload WINDOW [OUTPUT]", "Reload stream of window by number on output or name.", cobra.RangeArgs(1, 2), ctlWindow),
+		newCtlCmd(control.CommandLayout, "layout [OUTPUT]", "Activate layout view.", cobra.MaximumNArgs(1), ctlOutput),
+		newCtlCmd(control.CommandLayout, "switch LAYOUT [OUTPUT]", "Switch to named layout by name, next, or previous.", cobra.RangeArgs(1, 2), ctlLayout),
+		newCtlCmd(control.CommandNext, "next [OUTPUT]", "Show next window in fullscreen view.", cobra.MaximumNArgs(1), ctlOutput),
+		newCtlCmd(control.CommandPrevious, "previous [OUTPUT]", "Show previous window in fullscreen view.", cobra.MaximumNArgs(1), ctlOutput),
+		newCtlCmd(control.CommandPage, "page PAGE [OUTPUT]", "Activate layout view of page by number, next, or previous.", cobra.RangeArgs(1, 2), ctlPage),
+		newCtlCmd(control.CommandMute, "mute [OUTPUT]", "Mute audio.", cobra.MaximumNArgs(1), ctlOutput),
+		newCtlCmd(control.CommandUnmute, "unmute [OUTPUT]", "Unmute audio of fullscreen or focused window.", cobra.MaximumNArgs(1), ctlOutput),
+	)
+}
@@ -2,31 +2,58 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"net/url"
 	"strconv"
+	"time"
 
-	"github.com/ItsNotGoodName/x-ipcviewer/mosaic"
 	"github.com/ItsNotGoodName/x-ipcviewer/mpv"
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
 	"github.com/spf13/viper"
 )
 
+const (
+	// DefaultFocusBorderWidth is the border width of the focused window.
+	DefaultFocusBorderWidth = 2
+	// DefaultFocusColor is the border color of the focused window.
+	DefaultFocusColor = "#ffffff"
+	// DefaultLabelFontSize is the font size of labels relative to a window height of 720 pixels.
+	DefaultLabelFontSize = 24
+	// DefaultEntryTimeout is how long to wait for the next digit of a window number.
+	DefaultEntryTimeout = 2 * time.Second
+)
+
 type Config struct {
-	Background          bool
-	ConfigWatchExit     bool
-	Layout              Layout
-	LayoutManualWindows []mosaic.LayoutManualWindow `mapstructure:"-"`
-	Player              Player
-	Windows             []Window
+	Background       bool
+	ConfigWatchExit  bool
+	ControlSocket    string
+	EntryTimeout     time.Duration
+	FocusBorderWidth int
+	FocusColor       string
+	FocusColorValue  uint32           `mapstructure:"-"`
+	KeyBindings      []xwm.KeyBinding `mapstructure:"-"`
+	Label            Label
+	LayoutConfig     `mapstructure:",squash"`
+	Layouts          []NamedLayout
+	Outputs          []Output
+	Player           Player
+	Reconnect        mpv.ReconnectPolicy
+	Tour             Tour
+	Windows          []Window
 }
 
-type Layout string
-
-func (c Layout) IsAuto() bool {
-	return c == "" || c == "auto"
+// Output overrides the layout of the monitor with the same name.
+type Output struct {
+	Name         string
+	LayoutConfig `mapstructure:",squash"`
 }
 
-func (c Layout) IsManual() bool {
-	return c == "manual"
+// Label is drawn on top of each window.
+type Label struct {
+	Show     bool
+	Clock    bool
+	Position string
+	FontSize int
 }
 
 type Player struct {
@@ -35,23 +62,54 @@ type Player struct {
 }
 
 type Window struct {
-	Name       string
-	Main       string
-	Sub        string
-	LowLatency bool
-	Flags      []string
+	Name string
+	Main string
+	Sub  string
+	// MainFallbacks and SubFallbacks are tried in order when the stream before them fails.
+	MainFallbacks []string
+	SubFallbacks  []string
+	LowLatency    bool
+	Flags         []string
+	Output        string
+	// Reconnect overrides fields of the global Reconnect that are not zero.
+	Reconnect mpv.ReconnectPolicy
 }
 
 func Parse(cfg *Config) error {
 	viper.SetDefault("Player.GPU", mpv.DefaultGPU)
+	viper.SetDefault("Tour.Idle", DefaultTourIdle)
+	viper.SetDefault("FocusBorderWidth", DefaultFocusBorderWidth)
+	viper.SetDefault("FocusColor", DefaultFocusColor)
+	viper.SetDefault("EntryTimeout", DefaultEntryTimeout)
+	viper.SetDefault("Reconnect.InitialDelay", mpv.DefaultReconnectPolicy.InitialDelay)
+	viper.SetDefault("Reconnect.MaxDelay", mpv.DefaultReconnectPolicy.MaxDelay)
+	viper.SetDefault("Reconnect.Multiplier", mpv.DefaultReconnectPolicy.Multiplier)
+	viper.SetDefault("Reconnect.Jitter", *mpv.DefaultReconnectPolicy.Jitter)
+	viper.SetDefault("Reconnect.StallTimeout", mpv.DefaultReconnectPolicy.StallTimeout)
+	viper.SetDefault("Reconnect.FailbackInterval", mpv.DefaultReconnectPolicy.FailbackInterval)
+	viper.SetDefault("Label.Show", true)
+	viper.SetDefault("Label.Position", xwm.LabelTopLeft)
+	viper.SetDefault("Label.FontSize", DefaultLabelFontSize)
 
 	if err := viper.Unmarshal(cfg); err != nil {
 		return err
 	}
 
+	// Parse Reconnect
+	if err := cfg.Reconnect.Validate(); err != nil {
+		return fmt.Errorf("Reconnect.%w", err)
+	}
+
 	// Parse Windows
 	for i := range cfg.Windows {
-		cfg.Windows[i].Flags = append(cfg.Player.Flags, cfg.Windows[i].Flags...)
+		cfg.Windows[i].Flags = append(append([]string{}, cfg.Player.Flags...), cfg.Windows[i].Flags...)
+		cfg.Windows[i].Reconnect = cfg.Windows[i].Reconnect.Merge(cfg.Reconnect)
+		if err := cfg.Windows[i].Reconnect.Validate(); err != nil {
+			return fmt.Errorf("Windows[%d].Reconnect.%w", i, err)
+		}
+		if len(cfg.Windows[i].SubFallbacks) > 0 && (cfg.Windows[i].Sub == "" || cfg.Windows[i].Sub == cfg.Windows[i].Main) {
+			return fmt.Errorf("Windows[%d].SubFallbacks: needs a Sub that is different from Main, use MainFallbacks", i)
+		}
 		if cfg.Windows[i].Name == "" {
 			cfg.Windows[i].Name = strconv.Itoa(i)
 
@@ -64,23 +122,196 @@ func Parse(cfg *Config) error {
 		}
 	}
 
-	// Parse LayoutManualWindows
-	var clm ConfigLayoutManual
-	if err := viper.Unmarshal(&clm); err != nil {
+	// Parse focus
+	if cfg.FocusBorderWidth < 0 || cfg.FocusBorderWidth > math.MaxUint16 {
+		return fmt.Errorf("FocusBorderWidth=%d: must be between 0 and %d", cfg.FocusBorderWidth, math.MaxUint16)
+	}
+	focusColor, err := parseColor(cfg.FocusColor)
+	if err != nil {
+		return fmt.Errorf("FocusColor=%w", err)
+	}
+	cfg.FocusColorValue = focusColor
+
+	if cfg.EntryTimeout <= 0 {
+		return fmt.Errorf("EntryTimeout=%s: must be greater than 0", cfg.EntryTimeout)
+	}
+
+	// Parse Label
+	if !contains([]string{xwm.LabelTopLeft, xwm.LabelTopRight, xwm.LabelBottomLeft, xwm.LabelBottomRight}, cfg.Label.Position) {
+		return fmt.Errorf("Label.Position=%s: invalid position", cfg.Label.Position)
+	}
+	if cfg.Label.FontSize < 1 {
+		return fmt.Errorf("Label.FontSize=%d: must be greater than 0", cfg.Label.FontSize)
+	}
+
+	// Parse LayoutConfig
+	if err := parseLayoutConfig(&cfg.LayoutConfig); err != nil {
+		return err
+	}
+	if err := cfg.checkLayout(cfg.Layout); err != nil {
+		return fmt.Errorf("Layout=%w", err)
+	}
+
+	// Parse Layouts
+	for i := range cfg.Layouts {
+		if err := parseLayoutConfig(&cfg.Layouts[i].LayoutConfig); err != nil {
+			return fmt.Errorf("Layouts[%d].%w", i, err)
+		}
+		if !cfg.Layouts[i].Layout.IsBuiltin() {
+			return fmt.Errorf("Layouts[%d].Layout=%s: unknown layout", i, cfg.Layouts[i].Layout)
+		}
+		if cfg.Layouts[i].Name == "" || Layout(cfg.Layouts[i].Name).IsBuiltin() || cfg.Layouts[i].Name == xwm.ArgNext || cfg.Layouts[i].Name == xwm.ArgPrevious {
+			return fmt.Errorf("Layouts[%d].Name=%s: invalid name", i, cfg.Layouts[i].Name)
+		}
+	}
+
+	// Parse KeyBindings
+	var ckb ConfigKeyBindings
+	if err := viper.Unmarshal(&ckb); err != nil {
 		return err
 	}
-	for i, lm := range clm.LayoutManual {
-		lmw, err := parseLayoutManualWindow(lm)
+	if !viper.IsSet("KeyBindings") {
+		ckb.KeyBindings = DefaultKeyBindings
+	}
+	for i, kb := range ckb.KeyBindings {
+		xkb, err := parseKeyBinding(kb)
 		if err != nil {
-			return fmt.Errorf("LayoutManual[%d].%w", i, err)
+			return fmt.Errorf("KeyBindings[%d].%w", i, err)
 		}
 
-		cfg.LayoutManualWindows = append(cfg.LayoutManualWindows, lmw)
+		cfg.KeyBindings = append(cfg.KeyBindings, xkb)
+	}
+
+	// Parse Tour
+	var ct ConfigTour
+	if err := viper.Unmarshal(&ct); err != nil {
+		return err
+	}
+	for i, cts := range ct.Tour.Steps {
+		ts, err := parseTourStep(cts)
+		if err != nil {
+			return fmt.Errorf("Tour.Steps[%d].%w", i, err)
+		}
+
+		cfg.Tour.Steps = append(cfg.Tour.Steps, ts)
+	}
+
+	// Parse Outputs
+	for i := range cfg.Outputs {
+		if err := parseLayoutConfig(&cfg.Outputs[i].LayoutConfig); err != nil {
+			return fmt.Errorf("Outputs[%d].%w", i, err)
+		}
+		if err := cfg.checkLayout(cfg.Outputs[i].Layout); err != nil {
+			return fmt.Errorf("Outputs[%d].Layout=%w", i, err)
+		}
+	}
+
+	// Check layout actions
+	for i, kb := range cfg.KeyBindings {
+		if err := cfg.checkLayoutAction(kb.Action); err != nil {
+			return fmt.Errorf("KeyBindings[%d].Action=%w", i, err)
+		}
+	}
+	for i, ts := range cfg.Tour.Steps {
+		if err := cfg.checkLayoutAction(ts.Action); err != nil {
+			return fmt.Errorf("Tour.Steps[%d].Action=%w", i, err)
+		}
 	}
 
 	return nil
 }
 
+// OutputWindows returns the indexes of the windows that belong on the output.
+// Windows without an output or with an output that is not connected belong on the primary output.
+func (c *Config) OutputWindows(output string, primary bool, connected []string) []int {
+	var indexes []int
+	for i, window := range c.Windows {
+		if window.Output == output || (primary && !contains(connected, window.Output)) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// OutputLayout returns the name and config of the default layout of the output.
+// The name is empty when the layout is not a named layout.
+func (c *Config) OutputLayout(output string) (string, LayoutConfig) {
+	lc := c.LayoutConfig
+	for _, o := range c.Outputs {
+		if o.Name == output {
+			lc = o.LayoutConfig
+			break
+		}
+	}
+
+	if !lc.Layout.IsBuiltin() {
+		name := string(lc.Layout)
+		if nlc, ok := c.NamedLayout(name); ok {
+			return name, nlc
+		}
+	}
+
+	return "", lc
+}
+
+// NamedLayout returns the config of the named layout.
+func (c *Config) NamedLayout(name string) (LayoutConfig, bool) {
+	for _, nl := range c.Layouts {
+		if nl.Name == name {
+			return nl.LayoutConfig, true
+		}
+	}
+
+	return LayoutConfig{}, false
+}
+
+// LayoutNames returns the names of the named layouts in order.
+func (c *Config) LayoutNames() []string {
+	var names []string
+	for _, nl := range c.Layouts {
+		names = append(names, nl.Name)
+	}
+
+	return names
+}
+
+// checkLayout returns an error when the layout is not builtin or a named layout.
+func (c *Config) checkLayout(layout Layout) error {
+	if layout.IsBuiltin() {
+		return nil
+	}
+
+	if _, ok := c.NamedLayout(string(layout)); !ok {
+		return fmt.Errorf("%s: unknown layout", layout)
+	}
+
+	return nil
+}
+
+// checkLayoutAction returns an error when the action switches to a named layout that does not exist.
+func (c *Config) checkLayoutAction(action xwm.Action) error {
+	if action.Name != xwm.ActionLayout || action.Arg == "" || action.Arg == xwm.ArgNext || action.Arg == xwm.ArgPrevious {
+		return nil
+	}
+
+	if _, ok := c.NamedLayout(action.Arg); !ok {
+		return fmt.Errorf("%s: unknown layout", action)
+	}
+
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
 func parseHostname(maybeUrl string) (string, error) {
 	u, err := url.Parse(maybeUrl)
 	if err != nil {
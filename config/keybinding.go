@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/xkeysym"
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+type ConfigKeyBindings struct {
+	KeyBindings []KeyBinding
+}
+
+type KeyBinding struct {
+	Key    string
+	Action string
+}
+
+var DefaultKeyBindings = []KeyBinding{
+	{Key: "q", Action: xwm.ActionQuit},
+	{Key: "0", Action: "digit:0"},
+	{Key: "1", Action: "digit:1"},
+	{Key: "2", Action: "digit:2"},
+	{Key: "3", Action: "digit:3"},
+	{Key: "4", Action: "digit:4"},
+	{Key: "5", Action: "digit:5"},
+	{Key: "6", Action: "digit:6"},
+	{Key: "7", Action: "digit:7"},
+	{Key: "8", Action: "digit:8"},
+	{Key: "9", Action: "digit:9"},
+	{Key: "KP_0", Action: "digit:0"},
+	{Key: "KP_1", Action: "digit:1"},
+	{Key: "KP_2", Action: "digit:2"},
+	{Key: "KP_3", Action: "digit:3"},
+	{Key: "KP_4", Action: "digit:4"},
+	{Key: "KP_5", Action: "digit:5"},
+	{Key: "KP_6", Action: "digit:6"},
+	{Key: "KP_7", Action: "digit:7"},
+	{Key: "KP_8", Action: "digit:8"},
+	{Key: "KP_9", Action: "digit:9"},
+	{Key: "Return", Action: xwm.ActionEnter},
+	{Key: "KP_Enter", Action: xwm.ActionEnter},
+	{Key: "Escape", Action: xwm.ActionCancel},
+	{Key: "Page_Down", Action: "page:next"},
+	{Key: "Page_Up", Action: "page:previous"},
+	{Key: "space", Action: xwm.ActionNext},
+	{Key: "BackSpace", Action: xwm.ActionPrevious},
+	{Key: "l", Action: xwm.ActionLabels},
+	{Key: "Left", Action: "focus:left"},
+	{Key: "Right", Action: "focus:right"},
+	{Key: "Up", Action: "focus:up"},
+	{Key: "Down", Action: "focus:down"},
+}
+
+func parseKeyBinding(kb KeyBinding) (xwm.KeyBinding, error) {
+	keysym, ok := xkeysym.Keysym(kb.Key)
+	if !ok {
+		return xwm.KeyBinding{}, fmt.Errorf("Key=%s: unknown keysym", kb.Key)
+	}
+
+	action, err := xwm.ParseAction(kb.Action)
+	if err != nil {
+		return xwm.KeyBinding{}, fmt.Errorf("Action=%w", err)
+	}
+
+	return xwm.KeyBinding{
+		Keysym: keysym,
+		Action: action,
+	}, nil
+}
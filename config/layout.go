@@ -2,14 +2,74 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
 	"github.com/ItsNotGoodName/x-ipcviewer/mosaic"
 )
 
-type ConfigLayoutManual struct {
-	LayoutManual []LayoutManual
+const (
+	// DefaultAspectRatio is the aspect ratio of cameras used by the auto layout.
+	DefaultAspectRatio = "16/9"
+	// DefaultBorderColor is the color of window borders.
+	DefaultBorderColor = "#000000"
+	// DefaultBackgroundColor is the color behind windows.
+	DefaultBackgroundColor = "#000000"
+)
+
+// LayoutConfig is everything a mosaic.Layout is created from.
+type LayoutConfig struct {
+	Layout               Layout
+	LayoutManual         []LayoutManual
+	LayoutManualWindows  []mosaic.LayoutManualWindow `mapstructure:"-"`
+	Columns              int
+	Rows                 int
+	LayoutSpan           []mosaic.LayoutSpanWindow
+	AspectRatio          string
+	AspectRatioValue     float32 `mapstructure:"-"`
+	Letterbox            bool
+	Gap                  int
+	BorderWidth          int
+	BorderColor          string
+	BorderColorValue     uint32 `mapstructure:"-"`
+	BackgroundColor      string
+	BackgroundColorValue uint32 `mapstructure:"-"`
+}
+
+// NamedLayout is a layout that can be switched to at runtime.
+type NamedLayout struct {
+	Name         string
+	LayoutConfig `mapstructure:",squash"`
+}
+
+type Layout string
+
+func (c Layout) IsAuto() bool {
+	return c == "" || c == "auto"
+}
+
+func (c Layout) IsManual() bool {
+	return c == "manual"
+}
+
+func (c Layout) IsGrid() bool {
+	return c == "grid"
+}
+
+func (c Layout) IsSpan() bool {
+	return c == "span"
+}
+
+// IsPreset returns true when the layout is the name of a mosaic preset such as 1+7.
+func (c Layout) IsPreset() bool {
+	_, ok := mosaic.NewLayoutPreset(string(c))
+	return ok
+}
+
+// IsBuiltin returns false when the layout is the name of a named layout.
+func (c Layout) IsBuiltin() bool {
+	return c.IsAuto() || c.IsManual() || c.IsGrid() || c.IsSpan() || c.IsPreset()
 }
 
 type LayoutManual struct {
@@ -43,6 +103,21 @@ func calculateRatio(ratio string) (float32, error) {
 	return 0, fmt.Errorf("%s: invalid float", ratio)
 }
 
+// parseColor parses a #rrggbb color into a pixel value of a 24-bit TrueColor visual.
+func parseColor(color string) (uint32, error) {
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 {
+		return 0, fmt.Errorf("%s: invalid color, must be #rrggbb", color)
+	}
+
+	pixel, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid color, must be #rrggbb", color)
+	}
+
+	return uint32(pixel), nil
+}
+
 func parseLayoutManualWindow(lm LayoutManual) (mosaic.LayoutManualWindow, error) {
 	x, err := calculateRatio(lm.X)
 	if err != nil {
@@ -71,3 +146,83 @@ func parseLayoutManualWindow(lm LayoutManual) (mosaic.LayoutManualWindow, error)
 		H: h,
 	}, nil
 }
+
+func parseLayoutConfig(lc *LayoutConfig) error {
+	if lc.AspectRatio == "" {
+		lc.AspectRatio = DefaultAspectRatio
+	}
+	aspectRatio, err := calculateRatio(lc.AspectRatio)
+	if err != nil {
+		return fmt.Errorf("AspectRatio=%w", err)
+	}
+	if aspectRatio <= 0 {
+		return fmt.Errorf("AspectRatio=%s: must be greater than 0", lc.AspectRatio)
+	}
+	lc.AspectRatioValue = aspectRatio
+
+	if lc.Gap < 0 || lc.Gap > math.MaxUint16 {
+		return fmt.Errorf("Gap=%d: must be between 0 and %d", lc.Gap, math.MaxUint16)
+	}
+
+	if lc.BorderWidth < 0 || lc.BorderWidth > math.MaxUint16 {
+		return fmt.Errorf("BorderWidth=%d: must be between 0 and %d", lc.BorderWidth, math.MaxUint16)
+	}
+
+	if lc.BorderColor == "" {
+		lc.BorderColor = DefaultBorderColor
+	}
+	if lc.BorderColorValue, err = parseColor(lc.BorderColor); err != nil {
+		return fmt.Errorf("BorderColor=%w", err)
+	}
+
+	if lc.BackgroundColor == "" {
+		lc.BackgroundColor = DefaultBackgroundColor
+	}
+	if lc.BackgroundColorValue, err = parseColor(lc.BackgroundColor); err != nil {
+		return fmt.Errorf("BackgroundColor=%w", err)
+	}
+
+	lc.LayoutManualWindows = nil
+	for i, lm := range lc.LayoutManual {
+		lmw, err := parseLayoutManualWindow(lm)
+		if err != nil {
+			return fmt.Errorf("LayoutManual[%d].%w", i, err)
+		}
+
+		lc.LayoutManualWindows = append(lc.LayoutManualWindows, lmw)
+	}
+
+	if (lc.Layout.IsGrid() || lc.Layout.IsSpan()) && (lc.Columns < 1 || lc.Rows < 1) {
+		return fmt.Errorf("Columns=%d,Rows=%d: must be greater than 0", lc.Columns, lc.Rows)
+	}
+
+	if lc.Layout.IsSpan() {
+		for i, ls := range lc.LayoutSpan {
+			if err := checkLayoutSpanWindow(ls, lc.Columns, lc.Rows); err != nil {
+				return fmt.Errorf("LayoutSpan[%d].%w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkLayoutSpanWindow(ls mosaic.LayoutSpanWindow, columns, rows int) error {
+	rowSpan, colSpan := ls.RowSpan, ls.ColSpan
+	if rowSpan == 0 {
+		rowSpan = 1
+	}
+	if colSpan == 0 {
+		colSpan = 1
+	}
+
+	if ls.Row < 0 || rowSpan < 0 || ls.Row+rowSpan > rows {
+		return fmt.Errorf("Row=%d,RowSpan=%d: outside of %d rows", ls.Row, ls.RowSpan, rows)
+	}
+
+	if ls.Col < 0 || colSpan < 0 || ls.Col+colSpan > columns {
+		return fmt.Errorf("Col=%d,ColSpan=%d: outside of %d columns", ls.Col, ls.ColSpan, columns)
+	}
+
+	return nil
+}
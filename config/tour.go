@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+const (
+	DefaultTourIdle     = time.Minute
+	DefaultTourDuration = 10 * time.Second
+)
+
+type ConfigTour struct {
+	Tour struct {
+		Steps []ConfigTourStep
+	}
+}
+
+type ConfigTourStep struct {
+	Action   string
+	Duration time.Duration
+}
+
+type Tour struct {
+	// Idle is how long to wait after user input before resuming.
+	Idle time.Duration
+	// Output to tour, all outputs are toured when empty.
+	Output string
+	Steps  []TourStep `mapstructure:"-"`
+}
+
+type TourStep struct {
+	Action   xwm.Action
+	Duration time.Duration
+}
+
+func parseTourStep(cts ConfigTourStep) (TourStep, error) {
+	action, err := xwm.ParseAction(cts.Action)
+	if err != nil {
+		return TourStep{}, fmt.Errorf("Action=%w", err)
+	}
+
+	switch action.Name {
+	case xwm.ActionFullscreen, xwm.ActionLayout, xwm.ActionPage:
+	default:
+		return TourStep{}, fmt.Errorf("Action=%s: not allowed in tour", cts.Action)
+	}
+
+	duration := cts.Duration
+	if duration <= 0 {
+		duration = DefaultTourDuration
+	}
+
+	return TourStep{
+		Action:   action,
+		Duration: duration,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+)
+
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		conn:    conn,
+		scanner: bufio.NewScanner(conn),
+	}, nil
+}
+
+// Do sends the request and waits for the response, the response's error is returned as an error.
+func (c *Client) Do(req Request) (Response, error) {
+	if err := json.NewEncoder(c.conn).Encode(req); err != nil {
+		return Response{}, err
+	}
+
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return Response{}, err
+		}
+		return Response{}, errors.New("connection closed")
+	}
+
+	var res Response
+	if err := json.Unmarshal(c.scanner.Bytes(), &res); err != nil {
+		return Response{}, err
+	}
+
+	if res.Error != "" {
+		return res, errors.New(res.Error)
+	}
+
+	return res, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
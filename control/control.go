@@ -0,0 +1,154 @@
+// control is a JSON lines protocol over a unix socket for controlling a running instance.
+package control
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// CommandStatus returns the status.
+	CommandStatus = "status"
+	// CommandFullscreen toggles fullscreen view of Window on Output.
+	CommandFullscreen = "fullscreen"
+	// CommandLayout activates layout view or switches to the named Layout on Output or all outputs.
+	CommandLayout = "layout"
+	// CommandNext shows the next window in fullscreen view on Output or all outputs.
+	CommandNext = "next"
+	// CommandPrevious shows the previous window in fullscreen view on Output or all outputs.
+	CommandPrevious = "previous"
+	// CommandMute mutes audio on Output or all outputs.
+	CommandMute = "mute"
+	// CommandUnmute unmutes audio on Output or all outputs.
+	CommandUnmute = "unmute"
+	// CommandReload reloads the stream of Window on Output.
+	CommandReload = "reload"
+	// CommandPage activates layout view of Page on Output or all outputs.
+	CommandPage = "page"
+)
+
+type Request struct {
+	Command string `json:"command"`
+	// Window is a window name or a window number on Output, starting from 1, that is the same number as key bindings.
+	Window string `json:"window,omitempty"`
+	// Output is the name of an output.
+	Output string `json:"output,omitempty"`
+	// Page is a page number, starting from 1, "next", or "previous".
+	Page string `json:"page,omitempty"`
+	// Layout is a layout name, "next", or "previous".
+	Layout string `json:"layout,omitempty"`
+}
+
+type Response struct {
+	Error  string  `json:"error,omitempty"`
+	Status *Status `json:"status,omitempty"`
+}
+
+type Status struct {
+	Outputs []OutputStatus `json:"outputs"`
+}
+
+type OutputStatus struct {
+	Name    string         `json:"name"`
+	Layout  string         `json:"layout"`
+	Muted   bool           `json:"muted"`
+	Page    int            `json:"page"`
+	Pages   int            `json:"pages"`
+	Windows []WindowStatus `json:"windows"`
+}
+
+type WindowStatus struct {
+	// Number is the window number on the output, starting from 1.
+	Number     int    `json:"number"`
+	Name       string `json:"name"`
+	Fullscreen bool   `json:"fullscreen"`
+	Focused    bool   `json:"focused"`
+	Error      string `json:"error,omitempty"`
+	// State is the state of the stream from the player's watchdog.
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+	// LastFrame is when the stream last made progress in RFC 3339.
+	LastFrame string `json:"lastFrame,omitempty"`
+	// Fallback is the index of the fallback URL that is playing, 0 is the primary URL.
+	Fallback int `json:"fallback,omitempty"`
+}
+
+// SocketPath returns the socket path for the X display.
+func SocketPath(display string) string {
+	return socketDirs()[0].path(normalizeDisplay(display))
+}
+
+// FindSocketPath returns the socket path for the X display.
+// It looks in every directory SocketPath might have used, because SSH and cron can have a different environment than the X session.
+// When display is empty (e.g. from SSH or cron), it returns the only socket of the user.
+func FindSocketPath(display string) (string, error) {
+	dirs := socketDirs()
+
+	if display != "" {
+		display = normalizeDisplay(display)
+		for _, dir := range dirs {
+			if _, err := os.Stat(dir.path(display)); err == nil {
+				return dir.path(display), nil
+			}
+		}
+
+		return SocketPath(display), nil
+	}
+
+	var matches []string
+	var patterns []string
+	for _, dir := range dirs {
+		pattern := dir.path("*")
+		found, err := filepath.Glob(pattern)
+		if err != nil {
+			return "", err
+		}
+
+		matches = append(matches, found...)
+		patterns = append(patterns, pattern)
+	}
+	if len(matches) != 1 {
+		return "", fmt.Errorf("%s: found %d sockets, need exactly 1 when DISPLAY is not set", strings.Join(patterns, ", "), len(matches))
+	}
+
+	return matches[0], nil
+}
+
+// normalizeDisplay returns the display number of the X display, so ":0", ":0.0", and "localhost:0" give the same socket.
+func normalizeDisplay(display string) string {
+	if i := strings.LastIndex(display, ":"); i != -1 {
+		display = display[i+1:]
+	}
+	if i := strings.Index(display, "."); i != -1 {
+		display = display[:i]
+	}
+
+	return strings.ReplaceAll(display, "/", "_")
+}
+
+type socketDir struct {
+	dir string
+	// prefix is needed in shared directories to keep users apart
+	prefix string
+}
+
+func (d socketDir) path(display string) string {
+	return filepath.Join(d.dir, d.prefix+display+".sock")
+}
+
+// socketDirs returns the directories for sockets in order of preference, they are $XDG_RUNTIME_DIR, /run/user/<uid>, and the temporary directory.
+func socketDirs() []socketDir {
+	var dirs []socketDir
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		dirs = append(dirs, socketDir{dir: dir, prefix: "x-ipcviewer-"})
+	}
+
+	dir := fmt.Sprintf("/run/user/%d", os.Getuid())
+	if info, err := os.Stat(dir); err == nil && info.IsDir() && (len(dirs) == 0 || dirs[0].dir != dir) {
+		dirs = append(dirs, socketDir{dir: dir, prefix: "x-ipcviewer-"})
+	}
+
+	return append(dirs, socketDir{dir: os.TempDir(), prefix: fmt.Sprintf("x-ipcviewer-%d-", os.Getuid())})
+}
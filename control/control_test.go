@@ -0,0 +1,42 @@
+package control
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSocketPathNormalizesDisplay(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	want := SocketPath(":0")
+	for _, display := range []string{":0.0", "localhost:0", "localhost:0.1", "unix:0"} {
+		if got := SocketPath(display); got != want {
+			t.Errorf("SocketPath(%q) = %q, want %q", display, got, want)
+		}
+	}
+	if got := SocketPath(":1"); got == want {
+		t.Errorf("SocketPath(%q) = %q, want a different socket", ":1", got)
+	}
+}
+
+func TestFindSocketPathTriesEveryDir(t *testing.T) {
+	// The server ran without XDG_RUNTIME_DIR and used the temporary directory
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	want := filepath.Join(tmp, fmt.Sprintf("x-ipcviewer-%d-0.sock", os.Getuid()))
+	if err := os.WriteFile(want, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FindSocketPath(":0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("FindSocketPath = %q, want %q", got, want)
+	}
+}
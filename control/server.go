@@ -0,0 +1,84 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+)
+
+// Handler handles a request, it is called from multiple goroutines.
+type Handler func(req Request) Response
+
+type Server struct {
+	listener net.Listener
+	handler  Handler
+}
+
+// Listen on the unix socket, a stale socket from a previous instance is removed.
+func Listen(path string, handler Handler) (*Server, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s: already in use", path)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		listener: listener,
+		handler:  handler,
+	}, nil
+}
+
+// Serve accepts connections until the server is closed.
+func (s *Server) Serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Println("control.Server.Serve:", err)
+			}
+			return
+		}
+
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var res Response
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			res = Response{Error: err.Error()}
+		} else {
+			res = s.handler(req)
+		}
+
+		if err := encoder.Encode(res); err != nil {
+			log.Println("control.Server.serve:", err)
+			return
+		}
+	}
+}
+
+// Close the server and remove the socket.
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
@@ -0,0 +1,87 @@
+package mosaic
+
+import "math"
+
+// LayoutAuto is a grid that picks the column and row count that shows the most video for the aspect ratio and screen size.
+// The last partial row is centred.
+type LayoutAuto struct {
+	count     int
+	aspect    float64
+	letterbox bool
+}
+
+// NewLayoutAuto creates a LayoutAuto for count windows with videos of the aspect ratio (width / height).
+// Letterbox shrinks windows to the aspect ratio and centres them in their cells.
+func NewLayoutAuto(count int, aspect float64, letterbox bool) LayoutAuto {
+	if aspect <= 0 {
+		aspect = 16.0 / 9.0
+	}
+
+	return LayoutAuto{
+		count:     count,
+		aspect:    aspect,
+		letterbox: letterbox,
+	}
+}
+
+func (l LayoutAuto) Count() int {
+	return l.count
+}
+
+// columns returns the column count that maximizes the visible video area, more columns win ties.
+func (l LayoutAuto) columns(w, h uint16) int {
+	best, bestArea := 1, -1.0
+	for columns := l.count; columns >= 1; columns-- {
+		rows := (l.count + columns - 1) / columns
+		cw, ch := float64(w)/float64(columns), float64(h)/float64(rows)
+		vw, vh := cw, cw/l.aspect
+		if vh > ch {
+			vw, vh = ch*l.aspect, ch
+		}
+
+		if area := vw * vh; area > bestArea+1e-9 {
+			best, bestArea = columns, area
+		}
+	}
+
+	return best
+}
+
+func (l LayoutAuto) update(wins []Window, w, h uint16) {
+	if l.count == 0 {
+		return
+	}
+
+	columns := l.columns(w, h)
+	rows := (l.count + columns - 1) / columns
+
+	for i := range wins {
+		row, col := i/columns, i%columns
+
+		// Centre the last partial row
+		var shift uint16
+		if row == rows-1 {
+			if n := l.count - row*columns; n < columns {
+				shift = (w - edge(n, columns, w)) / 2
+			}
+		}
+
+		x0, x1 := shift+edge(col, columns, w), shift+edge(col+1, columns, w)
+		y0, y1 := edge(row, rows, h), edge(row+1, rows, h)
+		wins[i].X, wins[i].Y, wins[i].W, wins[i].H = x0, y0, x1-x0, y1-y0
+
+		if l.letterbox {
+			l.fit(&wins[i])
+		}
+	}
+}
+
+// fit shrinks the window to the aspect ratio and centres it in its cell.
+func (l LayoutAuto) fit(win *Window) {
+	vw := uint16(math.Min(float64(win.W), math.Round(float64(win.H)*l.aspect)))
+	vh := uint16(math.Min(float64(win.H), math.Round(float64(win.W)/l.aspect)))
+
+	win.X += (win.W - vw) / 2
+	win.Y += (win.H - vh) / 2
+	win.W, win.H = vw, vh
+}
@@ -0,0 +1,45 @@
+package mosaic
+
+// LayoutGap wraps a layout to leave gap pixels between windows and around the edges.
+// Windows are also shrunk by border pixels on each side so X window borders fit in the rectangles.
+type LayoutGap struct {
+	layout Layout
+	gap    uint16
+	border uint16
+}
+
+func NewLayoutGap(layout Layout, gap, border uint16) LayoutGap {
+	return LayoutGap{
+		layout: layout,
+		gap:    gap,
+		border: border,
+	}
+}
+
+func (l LayoutGap) Count() int {
+	return l.layout.Count()
+}
+
+func (l LayoutGap) update(wins []Window, w, h uint16) {
+	// Leave room for the gap at the right and bottom edges, each window takes the gap at its left and top edges
+	l.layout.update(wins, shrink(w, l.gap), shrink(h, l.gap))
+
+	for i := range wins {
+		wins[i].X += l.gap
+		wins[i].Y += l.gap
+		wins[i].W = shrink(wins[i].W, l.gap+2*l.border)
+		wins[i].H = shrink(wins[i].H, l.gap+2*l.border)
+	}
+}
+
+// shrink subtracts n from size without going below 1 pixel.
+func shrink(size, n uint16) uint16 {
+	if n == 0 {
+		return size
+	}
+	if size <= n {
+		return 1
+	}
+
+	return size - n
+}
@@ -30,15 +30,12 @@ func (l LayoutGrid) Count() int {
 }
 
 func (l LayoutGrid) update(wins []Window, w, h uint16) {
-	fw := uint16(float32(w) * (1.0 / float32(l.xc)))
-	fh := uint16(float32(h) * (1.0 / float32(l.yc)))
-
 	for i := 0; i < l.yc; i++ {
-		fy := fh * uint16(i)
+		y0, y1 := edge(i, l.yc, h), edge(i+1, l.yc, h)
 		for j := 0; j < l.xc; j++ {
-			fx := fw * uint16(j)
+			x0, x1 := edge(j, l.xc, w), edge(j+1, l.xc, w)
 			idx := (i * l.xc) + j
-			wins[idx].X, wins[idx].Y, wins[idx].W, wins[idx].H = fx, fy, fw, fh
+			wins[idx].X, wins[idx].Y, wins[idx].W, wins[idx].H = x0, y0, x1-x0, y1-y0
 		}
 	}
 }
@@ -1,5 +1,7 @@
 package mosaic
 
+import "math"
+
 type LayoutManualWindow struct {
 	X float32
 	Y float32
@@ -23,9 +25,22 @@ func (l LayoutManual) Count() int {
 
 func (l LayoutManual) update(wins []Window, w, h uint16) {
 	for i := range wins {
-		wins[i].X = uint16(l.windows[i].X * float32(w))
-		wins[i].Y = uint16(l.windows[i].Y * float32(h))
-		wins[i].W = uint16((l.windows[i].W+l.windows[i].X)*float32(w)) - wins[i].X
-		wins[i].H = uint16((l.windows[i].H+l.windows[i].Y)*float32(h)) - wins[i].Y
+		x0, x1 := ratioEdge(l.windows[i].X, w), ratioEdge(l.windows[i].X+l.windows[i].W, w)
+		y0, y1 := ratioEdge(l.windows[i].Y, h), ratioEdge(l.windows[i].Y+l.windows[i].H, h)
+		wins[i].X, wins[i].Y, wins[i].W, wins[i].H = x0, y0, x1-x0, y1-y0
+	}
+}
+
+// ratioEdge returns the pixel position of ratio of size.
+// Rounding to the nearest pixel makes windows that share a ratio, such as 1/3 and 1/3+1/3, share an edge.
+func ratioEdge(ratio float32, size uint16) uint16 {
+	pos := math.Round(float64(ratio) * float64(size))
+	if pos < 0 {
+		return 0
+	}
+	if pos > float64(size) {
+		return size
 	}
+
+	return uint16(pos)
 }
@@ -26,7 +26,18 @@ func New(layout Layout) Mosaic {
 	}
 }
 
+// SetLayout replaces the layout.
+func (m *Mosaic) SetLayout(layout Layout) {
+	m.layout = layout
+	m.windows = make([]Window, layout.Count())
+}
+
 func (m Mosaic) Windows(w, h uint16) []Window {
 	m.layout.update(m.windows, w, h)
 	return m.windows
 }
+
+// Count returns the number of windows in the layout.
+func (m Mosaic) Count() int {
+	return len(m.windows)
+}
@@ -0,0 +1,159 @@
+package mosaic
+
+import (
+	"fmt"
+	"testing"
+)
+
+var testResolutions = []struct{ w, h uint16 }{
+	{1920, 1080},
+	{1366, 768},
+	{1280, 1024},
+	{3840, 2160},
+	{1080, 1920},
+	{1001, 997},
+	{7, 5},
+}
+
+// checkWindows fails when windows are outside the screen or overlap, it returns the pixels covered by windows.
+func checkWindows(t *testing.T, wins []Window, w, h uint16) int {
+	t.Helper()
+
+	area := 0
+	for i, a := range wins {
+		if int(a.X)+int(a.W) > int(w) || int(a.Y)+int(a.H) > int(h) {
+			t.Errorf("window %d %+v: outside %dx%d", i, a, w, h)
+		}
+		area += int(a.W) * int(a.H)
+
+		for j := i + 1; j < len(wins); j++ {
+			b := wins[j]
+			if a.X < b.X+b.W && b.X < a.X+a.W && a.Y < b.Y+b.H && b.Y < a.Y+a.H {
+				t.Errorf("window %d %+v: overlaps window %d %+v", i, a, j, b)
+			}
+		}
+	}
+
+	// Windows do not overlap, so the union covers the sum of their areas
+	return area
+}
+
+func TestLayoutsTileScreen(t *testing.T) {
+	third := float32(1) / 3
+	layouts := []struct {
+		name   string
+		layout Layout
+	}{
+		{"grid 1x1", NewLayoutGrid(1, 1)},
+		{"grid 3x3", NewLayoutGrid(3, 3)},
+		{"grid 7x3", NewLayoutGrid(7, 3)},
+		{"grid count 5", NewLayoutGridCount(5)},
+		{"grid count 16", NewLayoutGridCount(16)},
+		{"manual halves", NewLayoutManual([]LayoutManualWindow{
+			{X: 0, Y: 0, W: .5, H: 1},
+			{X: .5, Y: 0, W: .5, H: .5},
+			{X: .5, Y: .5, W: .5, H: .5},
+		})},
+		{"manual thirds", NewLayoutManual([]LayoutManualWindow{
+			{X: 0, Y: 0, W: third, H: third},
+			{X: third, Y: 0, W: third, H: third},
+			{X: 2 * third, Y: 0, W: third, H: third},
+			{X: 0, Y: third, W: 1, H: third},
+			{X: 0, Y: 2 * third, W: 2 * third, H: third},
+			{X: 2 * third, Y: 2 * third, W: third, H: third},
+		})},
+		{"span", NewLayoutSpan(3, 3, []LayoutSpanWindow{
+			{Row: 0, Col: 0, RowSpan: 2, ColSpan: 2},
+			{Row: 0, Col: 2},
+			{Row: 1, Col: 2},
+			{Row: 2, Col: 0},
+			{Row: 2, Col: 1},
+			{Row: 2, Col: 2},
+		})},
+	}
+	for _, name := range Presets() {
+		preset, ok := NewLayoutPreset(name)
+		if !ok {
+			t.Fatalf("preset %s: not found", name)
+		}
+		layouts = append(layouts, struct {
+			name   string
+			layout Layout
+		}{"preset " + name, preset})
+	}
+
+	for _, tt := range layouts {
+		for _, r := range testResolutions {
+			t.Run(fmt.Sprintf("%s %dx%d", tt.name, r.w, r.h), func(t *testing.T) {
+				if area := checkWindows(t, New(tt.layout).Windows(r.w, r.h), r.w, r.h); area != int(r.w)*int(r.h) {
+					t.Errorf("windows cover %d pixels, want %d", area, int(r.w)*int(r.h))
+				}
+			})
+		}
+	}
+}
+
+func TestLayoutAuto(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		aspect    float64
+		letterbox bool
+		w, h      uint16
+		want      []Window
+	}{
+		{"1", 1, 16.0 / 9.0, false, 1920, 1080, []Window{
+			{0, 0, 1920, 1080},
+		}},
+		{"2x2", 4, 16.0 / 9.0, false, 1920, 1080, []Window{
+			{0, 0, 960, 540}, {960, 0, 960, 540},
+			{0, 540, 960, 540}, {960, 540, 960, 540},
+		}},
+		// 3 columns and 2 columns show the same video, more columns win and the last row is centred
+		{"3+2 centred", 5, 16.0 / 9.0, false, 1920, 1080, []Window{
+			{0, 0, 640, 540}, {640, 0, 640, 540}, {1280, 0, 640, 540},
+			{320, 540, 640, 540}, {960, 540, 640, 540},
+		}},
+		{"portrait column", 3, 16.0 / 9.0, false, 1080, 1920, []Window{
+			{0, 0, 1080, 640},
+			{0, 640, 1080, 640},
+			{0, 1280, 1080, 640},
+		}},
+		{"side by side", 2, 16.0 / 9.0, false, 1366, 768, []Window{
+			{0, 0, 683, 768}, {683, 0, 683, 768},
+		}},
+		{"letterbox", 2, 16.0 / 9.0, true, 1920, 1080, []Window{
+			{0, 270, 960, 540}, {960, 270, 960, 540},
+		}},
+		{"odd size", 7, 1, false, 1001, 997, []Window{
+			{0, 0, 333, 332}, {333, 0, 334, 332}, {667, 0, 334, 332},
+			{0, 332, 333, 332}, {333, 332, 334, 332}, {667, 332, 334, 332},
+			{334, 664, 333, 333},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(NewLayoutAuto(tt.count, tt.aspect, tt.letterbox)).Windows(tt.w, tt.h)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d windows, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("window %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLayoutAutoDoesNotOverlap(t *testing.T) {
+	for count := 1; count <= 25; count++ {
+		for _, aspect := range []float64{16.0 / 9.0, 4.0 / 3.0, 9.0 / 16.0} {
+			for _, r := range testResolutions {
+				t.Run(fmt.Sprintf("%d %.2f %dx%d", count, aspect, r.w, r.h), func(t *testing.T) {
+					checkWindows(t, New(NewLayoutAuto(count, aspect, false)).Windows(r.w, r.h), r.w, r.h)
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package mosaic
+
+import "sort"
+
+type preset struct {
+	columns int
+	rows    int
+	big     []LayoutSpanWindow
+}
+
+// presets are NVR-style layouts, big windows come first and the remaining cells are filled with small windows.
+var presets = map[string]preset{
+	"1+5":  {3, 3, []LayoutSpanWindow{{0, 0, 2, 2}}},
+	"1+7":  {4, 4, []LayoutSpanWindow{{0, 0, 3, 3}}},
+	"1+12": {4, 4, []LayoutSpanWindow{{1, 1, 2, 2}}},
+	"2+8":  {4, 4, []LayoutSpanWindow{{0, 0, 2, 2}, {0, 2, 2, 2}}},
+	"3+4":  {4, 4, []LayoutSpanWindow{{0, 0, 2, 2}, {0, 2, 2, 2}, {2, 0, 2, 2}}},
+	"4x4":  {4, 4, nil},
+	"5x5":  {5, 5, nil},
+	"6x6":  {6, 6, nil},
+	// Portrait presets stack full width big windows on top of 2 columns of small windows
+	"1+4v": {2, 4, []LayoutSpanWindow{{0, 0, 2, 2}}},
+	"1+6v": {2, 5, []LayoutSpanWindow{{0, 0, 2, 2}}},
+	"1+8v": {2, 6, []LayoutSpanWindow{{0, 0, 2, 2}}},
+	"2+4v": {2, 6, []LayoutSpanWindow{{0, 0, 2, 2}, {2, 0, 2, 2}}},
+	"2+8v": {2, 8, []LayoutSpanWindow{{0, 0, 2, 2}, {2, 0, 2, 2}}},
+}
+
+// newLayoutPreset creates a LayoutSpan with the big windows followed by small windows in every cell the big windows do not cover.
+func newLayoutPreset(columns, rows int, big ...LayoutSpanWindow) LayoutSpan {
+	covered := make([]bool, columns*rows)
+	for _, b := range big {
+		for row := b.Row; row < b.Row+b.RowSpan; row++ {
+			for col := b.Col; col < b.Col+b.ColSpan; col++ {
+				covered[row*columns+col] = true
+			}
+		}
+	}
+
+	windows := append([]LayoutSpanWindow{}, big...)
+	for i := range covered {
+		if !covered[i] {
+			windows = append(windows, LayoutSpanWindow{Row: i / columns, Col: i % columns, RowSpan: 1, ColSpan: 1})
+		}
+	}
+
+	return NewLayoutSpan(columns, rows, windows)
+}
+
+// NewLayoutPreset returns the preset layout with the name.
+func NewLayoutPreset(name string) (LayoutSpan, bool) {
+	preset, ok := presets[name]
+	if !ok {
+		return LayoutSpan{}, false
+	}
+
+	return newLayoutPreset(preset.columns, preset.rows, preset.big...), true
+}
+
+// Presets returns the sorted names of the preset layouts.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
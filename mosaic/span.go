@@ -0,0 +1,54 @@
+package mosaic
+
+// LayoutSpanWindow is a cell in a LayoutSpan that covers RowSpan rows and ColSpan columns starting from Row and Col.
+type LayoutSpanWindow struct {
+	Row     int
+	Col     int
+	RowSpan int
+	ColSpan int
+}
+
+// LayoutSpan is a grid where windows can cover multiple rows and columns.
+type LayoutSpan struct {
+	columns int
+	rows    int
+	windows []LayoutSpanWindow
+}
+
+func NewLayoutSpan(columns, rows int, windows []LayoutSpanWindow) LayoutSpan {
+	windows = append([]LayoutSpanWindow{}, windows...)
+	for i := range windows {
+		if windows[i].RowSpan < 1 {
+			windows[i].RowSpan = 1
+		}
+		if windows[i].ColSpan < 1 {
+			windows[i].ColSpan = 1
+		}
+	}
+
+	return LayoutSpan{
+		columns: columns,
+		rows:    rows,
+		windows: windows,
+	}
+}
+
+func (l LayoutSpan) Count() int {
+	return len(l.windows)
+}
+
+func (l LayoutSpan) update(wins []Window, w, h uint16) {
+	for i := range wins {
+		x0 := edge(l.windows[i].Col, l.columns, w)
+		x1 := edge(l.windows[i].Col+l.windows[i].ColSpan, l.columns, w)
+		y0 := edge(l.windows[i].Row, l.rows, h)
+		y1 := edge(l.windows[i].Row+l.windows[i].RowSpan, l.rows, h)
+		wins[i].X, wins[i].Y, wins[i].W, wins[i].H = x0, y0, x1-x0, y1-y0
+	}
+}
+
+// edge returns the pixel position of the i-th of n divisions of size.
+// Adjacent cells share edges so the divisions cover size without gaps.
+func edge(i, n int, size uint16) uint16 {
+	return uint16(i * int(size) / n)
+}
@@ -1,6 +1,7 @@
 package mpv
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 
@@ -10,7 +11,10 @@ import (
 
 func cmdCloser(cmd *exec.Cmd) closer.Closer {
 	return func() error {
-		return cmd.Process.Kill()
+		if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return err
+		}
+		return nil
 	}
 }
 
@@ -0,0 +1,112 @@
+package mpv
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+const (
+	labelOverlayID = iota + 1
+	placeholderOverlayID
+)
+
+// labelAlignments are ASS alignment tags for label positions.
+var labelAlignments = map[string]int{
+	xwm.LabelTopLeft:     7,
+	xwm.LabelTopRight:    9,
+	xwm.LabelBottomLeft:  1,
+	xwm.LabelBottomRight: 3,
+}
+
+func (p *Player) SetLabel(label xwm.Label) error {
+	p.mu.Lock()
+	p.label = label
+	p.mu.Unlock()
+
+	flag(p.labelC)
+	return nil
+}
+
+// drawLabel draws the label and the placeholder on top of the video with OSD overlays.
+func (p *Player) drawLabel() error {
+	p.mu.Lock()
+	label, status := p.label, p.status
+	p.mu.Unlock()
+
+	now := time.Now()
+
+	if label.Hidden || label.Text == "" {
+		if _, err := p.call("osd-overlay", labelOverlayID, "none", ""); err != nil {
+			return err
+		}
+	} else {
+		if _, err := p.call("osd-overlay", labelOverlayID, "ass-events", labelASS(label, status, now), 0, 720); err != nil {
+			return err
+		}
+	}
+
+	if !placeholder(status) {
+		_, err := p.call("osd-overlay", placeholderOverlayID, "none", "")
+		return err
+	}
+
+	_, err := p.call("osd-overlay", placeholderOverlayID, "ass-events", placeholderASS(p.name, status, now), 0, 720)
+	return err
+}
+
+// placeholder returns true when the stream should be playing but is not.
+func placeholder(status xwm.PlayerStatus) bool {
+	return status.State != xwm.StatePlaying && status.State != xwm.StateStopped
+}
+
+// placeholderTitles are the titles of placeholders for states.
+var placeholderTitles = map[string]string{
+	xwm.StateConnecting:   "CONNECTING",
+	xwm.StateStalled:      "STALLED",
+	xwm.StateReconnecting: "RECONNECTING",
+	xwm.StateFailed:       "NO SIGNAL",
+}
+
+// placeholderASS is the centered title, name, reason, and time since the last frame.
+func placeholderASS(name string, status xwm.PlayerStatus, now time.Time) string {
+	lines := []string{
+		fmt.Sprintf("{\\fs48\\b1}%s{\\fs32\\b0}", placeholderTitles[status.State]),
+		escapeASS(name),
+	}
+	if status.Reason != "" {
+		lines = append(lines, escapeASS(status.Reason))
+	}
+	if !status.LastFrame.IsZero() {
+		lines = append(lines, fmt.Sprintf("last frame %s ago", now.Sub(status.LastFrame).Truncate(time.Second)))
+	}
+
+	return "{\\an5\\bord2}" + strings.Join(lines, "\\N")
+}
+
+func labelASS(label xwm.Label, status xwm.PlayerStatus, now time.Time) string {
+	alignment, ok := labelAlignments[label.Position]
+	if !ok {
+		alignment = labelAlignments[xwm.LabelTopLeft]
+	}
+
+	text := label.Text
+	if status.Fallback != 0 {
+		text += fmt.Sprintf("  fallback %d", status.Fallback)
+	}
+	if label.Clock {
+		text += "  " + now.Format("15:04:05")
+	}
+	if placeholder(status) {
+		text += "  (" + status.State + ")"
+	}
+
+	return fmt.Sprintf("{\\an%d\\fs%d\\bord2}%s", alignment, label.FontSize, escapeASS(text))
+}
+
+// escapeASS prevents text from being parsed as ASS tags, a word joiner after backslashes breaks escape sequences.
+func escapeASS(text string) string {
+	return strings.NewReplacer("\\", "\\\u2060", "{", "\\{", "\n", " ").Replace(text)
+}
@@ -1,9 +1,13 @@
 package mpv
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os/exec"
+	"sync"
+	"time"
 
 	"github.com/ItsNotGoodName/mpvipc"
 	"github.com/ItsNotGoodName/x-ipcviewer/closer"
@@ -18,17 +22,34 @@ const (
 	event_demuxer_cache_time
 )
 
+// Player is a mpv process that is respawned when it exits.
 type Player struct {
 	name       string
-	conn       *mpvipc.Connection
+	args       []string
+	socketPath string
 	streamC    chan string
+	reloadC    chan struct{}
+	labelC     chan struct{}
 	lowLatency bool
-	closers    []int
+	fallbacks  map[string][]string
+	ctx        context.Context
+	cancel     context.CancelFunc
+	// watchdog is only used by run
+	watchdog *watchdog
+
+	mu      sync.Mutex
+	conn    *mpvipc.Connection
+	closers []int
+	muted   bool
+	label   xwm.Label
+	status  xwm.PlayerStatus
+	err     error
 }
 
 const DefaultGPU string = "auto"
 
-func NewPlayerFactory(name string, flags []string, gpu string, lowLatency bool) xwm.PlayerFactory {
+// NewPlayerFactory creates players, fallbacks maps a stream to the URLs that are tried in order when it fails.
+func NewPlayerFactory(name string, flags []string, gpu string, lowLatency bool, reconnect ReconnectPolicy, fallbacks map[string][]string) xwm.PlayerFactory {
 	return func(wid xproto.Window) (xwm.Player, error) {
 		socketPath := fmt.Sprintf("/tmp/x-ipcviewer-mpv-%s", uuid.New())
 
@@ -55,75 +76,177 @@ func NewPlayerFactory(name string, flags []string, gpu string, lowLatency bool)
 		// Flags
 		args = append(args, flags...)
 
-		var closers []int
-
-		// Mpv cmd
-		cmd := exec.Command("mpv", args...)
-		cmd.Stdout = NewLogWriter(name)
-
-		closers = append(closers, closer.Add(cmdCloser(cmd)))
+		ctx, cancel := context.WithCancel(context.Background())
+		p := &Player{
+			name:       name,
+			args:       args,
+			socketPath: socketPath,
+			streamC:    make(chan string, 1),
+			reloadC:    make(chan struct{}, 1),
+			labelC:     make(chan struct{}, 1),
+			lowLatency: lowLatency,
+			fallbacks:  fallbacks,
+			ctx:        ctx,
+			cancel:     cancel,
+			watchdog:   newWatchdog(reconnect),
+			status:     xwm.PlayerStatus{State: xwm.StateStopped},
+		}
 
-		// Start mpv
-		if err := cmd.Start(); err != nil {
-			closer.Close(closers...)
+		eventC, exitC, err := p.start()
+		if err != nil {
+			cancel()
 			return nil, err
 		}
 
-		// Open mpv connection
-		conn := mpvipc.NewConnection(socketPath)
+		// Watch mpv events and process
+		go p.run(eventC, exitC)
 
-		closers = append(closers, closer.Add(connectionCloser(conn, socketPath)))
+		return p, nil
+	}
+}
 
-		// Listen for mpv events
-		var eventC <-chan *mpvipc.Event
-		if err := retry.Do(func() error {
-			var err error
-			eventC, err = conn.Open(50)
-			return err
-		}, retry.Attempts(2000), retry.DelayType(retry.FixedDelay)); err != nil {
-			closer.Close(closers...)
-			return nil, err
+// start mpv and connect to it.
+func (p *Player) start() (<-chan *mpvipc.Event, <-chan struct{}, error) {
+	var closers []int
+
+	// Mpv cmd
+	cmd := exec.Command("mpv", p.args...)
+	cmd.Stdout = NewLogWriter(p.name)
+
+	closers = append(closers, closer.Add(cmdCloser(cmd)))
+
+	// Start mpv
+	if err := cmd.Start(); err != nil {
+		closer.Remove(closers...)
+		return nil, nil, err
+	}
+
+	// Wait for mpv to exit
+	exitC := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		log.Printf("mpv.Player.start: %s: exited: %v", p.name, err)
+		close(exitC)
+	}()
+
+	// Open mpv connection
+	conn := mpvipc.NewConnection(p.socketPath)
+
+	closers = append(closers, closer.Add(connectionCloser(conn, p.socketPath)))
+
+	// Listen for mpv events
+	var eventC <-chan *mpvipc.Event
+	if err := retry.Do(func() error {
+		select {
+		case <-exitC:
+			return retry.Unrecoverable(errors.New("mpv exited"))
+		default:
 		}
 
-		// Setup mpv event observers
-		_, err := conn.Call("observe_property", event_demuxer_cache_idle, "demuxer-cache-idle")
-		if err != nil {
-			closer.Close(closers...)
-			return nil, err
+		var err error
+		eventC, err = conn.Open(50)
+		return err
+	}, retry.Attempts(2000), retry.DelayType(retry.FixedDelay), retry.LastErrorOnly(true)); err != nil {
+		closer.Close(closers...)
+		return nil, nil, err
+	}
+
+	// Setup mpv event observers
+	_, err := conn.Call("observe_property", event_demuxer_cache_idle, "demuxer-cache-idle")
+	if err != nil {
+		closer.Close(closers...)
+		return nil, nil, err
+	}
+	_, err = conn.Call("observe_property", event_demuxer_cache_time, "demuxer-cache-time")
+	if err != nil {
+		closer.Close(closers...)
+		return nil, nil, err
+	}
+
+	p.mu.Lock()
+	// Released while starting
+	if p.ctx.Err() != nil {
+		p.mu.Unlock()
+		closer.Close(closers...)
+		return nil, nil, p.ctx.Err()
+	}
+	p.conn = conn
+	p.closers = closers
+	p.err = nil
+	err = p.conn.Set("volume", volume(p.muted))
+	p.mu.Unlock()
+	if err != nil {
+		log.Printf("mpv.Player.start: %s: volume: %s", p.name, err)
+	}
+
+	return eventC, exitC, nil
+}
+
+// run watches mpv and respawns it when it exits until the player is released.
+func (p *Player) run(eventC <-chan *mpvipc.Event, exitC <-chan struct{}) {
+	var stream string
+	for {
+		stream = p.watch(eventC, exitC, stream)
+
+		p.mu.Lock()
+		if err := closer.Close(p.closers...); err != nil {
+			log.Printf("mpv.Player.run: %s: %s", p.name, err)
 		}
-		_, err = conn.Call("observe_property", event_demuxer_cache_time, "demuxer-cache-time")
-		if err != nil {
-			closer.Close(closers...)
-			return nil, err
+		p.closers = nil
+		if p.ctx.Err() == nil {
+			p.err = errors.New("mpv exited")
 		}
+		p.mu.Unlock()
 
-		p := Player{
-			name:       name,
-			conn:       conn,
-			streamC:    make(chan string, 1),
-			lowLatency: lowLatency,
-			closers:    closers,
-		}
+		p.watchdog.exited()
+		p.updateStatus()
 
-		// Watch mpv events
-		go p.watch(eventC)
+		if p.ctx.Err() != nil {
+			return
+		}
 
-		return p, nil
+		log.Printf("mpv.Player.run: %s: respawning", p.name)
+		if err := retry.Do(func() error {
+			var err error
+			eventC, exitC, err = p.start()
+			return err
+		},
+			retry.Context(p.ctx),
+			retry.Attempts(^uint(0)),
+			retry.LastErrorOnly(true),
+			retry.Delay(time.Second),
+			retry.MaxDelay(time.Minute),
+			retry.DelayType(retry.BackOffDelay),
+			retry.OnRetry(func(n uint, err error) {
+				log.Printf("mpv.Player.run: %s: respawning: attempt %d: %s", p.name, n+1, err)
+			}),
+		); err != nil {
+			return
+		}
 	}
 }
 
-func (p Player) Mute(mute bool) error {
-	var value int
+func volume(mute bool) int {
 	if mute {
-		value = 0
-	} else {
-		value = 100
+		return 0
 	}
 
-	return p.conn.Set("volume", value)
+	return 100
 }
 
-func (p Player) Play(stream string) error {
+func (p *Player) Mute(mute bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.muted = mute
+	if p.closers == nil {
+		return nil
+	}
+
+	return p.conn.Set("volume", volume(mute))
+}
+
+func (p *Player) Play(stream string) error {
 	for {
 		select {
 		case p.streamC <- stream:
@@ -133,7 +256,7 @@ func (p Player) Play(stream string) error {
 	}
 }
 
-func (p Player) Stop() error {
+func (p *Player) Stop() error {
 	for {
 		select {
 		case p.streamC <- "":
@@ -143,8 +266,57 @@ func (p Player) Stop() error {
 	}
 }
 
-func (p Player) Release() {
+func (p *Player) Reload() error {
+	flag(p.reloadC)
+	return nil
+}
+
+func (p *Player) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+func (p *Player) Status() xwm.PlayerStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.status
+}
+
+// streams returns the URLs of the stream starting with the stream itself.
+func (p *Player) streams(stream string) []string {
+	if stream == "" {
+		return nil
+	}
+
+	return append([]string{stream}, p.fallbacks[stream]...)
+}
+
+// updateStatus copies the watchdog's status and redraws the label when the state changes.
+func (p *Player) updateStatus() {
+	status := p.watchdog.status
+
+	p.mu.Lock()
+	changed := status.State != p.status.State || status.Reason != p.status.Reason || status.Fallback != p.status.Fallback
+	p.status = status
+	p.mu.Unlock()
+
+	if changed {
+		log.Printf("mpv.Player.updateStatus: %s: %s: %s: fallback %d", p.name, status.State, status.Reason, status.Fallback)
+		flag(p.labelC)
+	}
+}
+
+func (p *Player) Release() {
+	p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if err := closer.Close(p.closers...); err != nil {
 		log.Println("mpv.Player.Release:", err)
 	}
+	p.closers = nil
 }
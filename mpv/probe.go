@@ -0,0 +1,61 @@
+package mpv
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+)
+
+const probeTimeout = 5 * time.Second
+
+// probeDefaultPorts are the URL schemes over TCP that can be probed and their ports that are used when a URL does not have a port.
+var probeDefaultPorts = map[string]string{
+	"rtsp":  "554",
+	"rtsps": "322",
+	"rtmp":  "1935",
+	"rtmps": "443",
+	"http":  "80",
+	"https": "443",
+}
+
+// canProbe returns why the stream cannot be probed, e.g. its scheme is not over TCP (udp, srt).
+func canProbe(stream string) error {
+	u, err := url.Parse(stream)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+
+	if _, ok := probeDefaultPorts[u.Scheme]; !ok {
+		return fmt.Errorf("%s: cannot probe URL scheme", u.Scheme)
+	}
+
+	return nil
+}
+
+// probe checks if the stream is reachable without playing it.
+// URLs with a host are reachable when a TCP connection can be made, other streams are reachable when the file exists.
+func probe(stream string) error {
+	if err := canProbe(stream); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(stream)
+	if err != nil || u.Host == "" {
+		_, err := os.Stat(stream)
+		return err
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), probeDefaultPorts[u.Scheme])
+	}
+
+	conn, err := net.DialTimeout("tcp", host, probeTimeout)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
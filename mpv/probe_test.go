@@ -0,0 +1,44 @@
+package mpv
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProbe(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	file := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		stream string
+		ok     bool
+	}{
+		{"rtsp://admin:password@" + l.Addr().String() + "/cam", true},
+		{"http://" + l.Addr().String() + "/video", true},
+		{file, true},
+		{filepath.Join(t.TempDir(), "missing.mp4"), false},
+		{"srt://" + l.Addr().String(), false},
+		{"udp://" + l.Addr().String(), false},
+	}
+	for _, tt := range tests {
+		if err := probe(tt.stream); (err == nil) != tt.ok {
+			t.Errorf("probe(%q) = %v, want ok %t", tt.stream, err, tt.ok)
+		}
+	}
+
+	for _, stream := range []string{"srt://camera:9000", "udp://239.0.0.1:1234", "rtspt://camera/cam"} {
+		if err := canProbe(stream); err == nil {
+			t.Errorf("canProbe(%q) = nil, want error", stream)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package mpv
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ReconnectPolicy is how long to wait before reloading a stream that stopped playing.
+type ReconnectPolicy struct {
+	// InitialDelay is the delay before the first reload.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between reloads.
+	MaxDelay time.Duration
+	// Multiplier grows the delay after each failed reload.
+	Multiplier float64
+	// Jitter randomizes the delay by up to this fraction in either direction, nil is no jitter.
+	// It is a pointer so a Jitter of 0 can override a fallback's Jitter in Merge.
+	Jitter *float64
+	// StallTimeout is how long a stream can go without progress before it is stalled.
+	StallTimeout time.Duration
+	// FailbackInterval is how often the primary URL is probed while a fallback URL is playing.
+	FailbackInterval time.Duration
+}
+
+var defaultJitter = 0.2
+
+var DefaultReconnectPolicy = ReconnectPolicy{
+	InitialDelay:     time.Second,
+	MaxDelay:         time.Minute,
+	Multiplier:       2,
+	Jitter:           &defaultJitter,
+	StallTimeout:     10 * time.Second,
+	FailbackInterval: time.Minute,
+}
+
+// Merge returns the policy with zero and nil fields taken from fallback.
+func (r ReconnectPolicy) Merge(fallback ReconnectPolicy) ReconnectPolicy {
+	if r.InitialDelay == 0 {
+		r.InitialDelay = fallback.InitialDelay
+	}
+	if r.MaxDelay == 0 {
+		r.MaxDelay = fallback.MaxDelay
+	}
+	if r.Multiplier == 0 {
+		r.Multiplier = fallback.Multiplier
+	}
+	if r.Jitter == nil {
+		r.Jitter = fallback.Jitter
+	}
+	if r.StallTimeout == 0 {
+		r.StallTimeout = fallback.StallTimeout
+	}
+	if r.FailbackInterval == 0 {
+		r.FailbackInterval = fallback.FailbackInterval
+	}
+
+	return r
+}
+
+func (r ReconnectPolicy) Validate() error {
+	if r.InitialDelay <= 0 {
+		return fmt.Errorf("InitialDelay=%s: must be greater than 0", r.InitialDelay)
+	}
+	if r.MaxDelay < r.InitialDelay {
+		return fmt.Errorf("MaxDelay=%s: must not be less than InitialDelay", r.MaxDelay)
+	}
+	if r.Multiplier < 1 {
+		return fmt.Errorf("Multiplier=%g: must not be less than 1", r.Multiplier)
+	}
+	if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+		return fmt.Errorf("Jitter=%g: must be between 0 and 1", *r.Jitter)
+	}
+	if r.StallTimeout <= 0 {
+		return fmt.Errorf("StallTimeout=%s: must be greater than 0", r.StallTimeout)
+	}
+	if r.FailbackInterval <= 0 {
+		return fmt.Errorf("FailbackInterval=%s: must be greater than 0", r.FailbackInterval)
+	}
+
+	return nil
+}
+
+// delay returns the delay before the reload after attempt failed reloads, random is in [0, 1).
+func (r ReconnectPolicy) delay(attempt int, random float64) time.Duration {
+	d := float64(r.InitialDelay) * math.Pow(r.Multiplier, float64(attempt))
+	if d > float64(r.MaxDelay) {
+		d = float64(r.MaxDelay)
+	}
+
+	if r.Jitter != nil {
+		d *= 1 + *r.Jitter*(2*random-1)
+	}
+
+	return time.Duration(d)
+}
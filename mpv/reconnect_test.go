@@ -0,0 +1,96 @@
+package mpv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectPolicyDelay(t *testing.T) {
+	noJitter := 0.0
+	policy := DefaultReconnectPolicy
+	policy.Jitter = &noJitter
+
+	want := []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		16 * time.Second,
+		32 * time.Second,
+		time.Minute,
+		time.Minute,
+	}
+	for attempt, d := range want {
+		if got := policy.delay(attempt, 0.9); got != d {
+			t.Errorf("delay(%d) = %s, want %s", attempt, got, d)
+		}
+	}
+
+	// Large attempts stay capped instead of overflowing
+	if got := policy.delay(1000, 0.5); got != time.Minute {
+		t.Errorf("delay(1000) = %s, want %s", got, time.Minute)
+	}
+}
+
+func TestReconnectPolicyDelayJitter(t *testing.T) {
+	policy := DefaultReconnectPolicy
+	jitter := *policy.Jitter
+
+	for attempt := 0; attempt < 10; attempt++ {
+		base := policy.delay(attempt, 0.5)
+		low, high := time.Duration(float64(base)*(1-jitter)), time.Duration(float64(base)*(1+jitter))
+
+		for _, random := range []float64{0, 0.25, 0.5, 0.75, 0.999} {
+			if got := policy.delay(attempt, random); got < low || got > high {
+				t.Errorf("delay(%d, %g) = %s, want between %s and %s", attempt, random, got, low, high)
+			}
+		}
+
+		if got := policy.delay(attempt, 0); got != low {
+			t.Errorf("delay(%d, 0) = %s, want %s", attempt, got, low)
+		}
+	}
+}
+
+func TestReconnectPolicyMerge(t *testing.T) {
+	noJitter := 0.0
+	policy := ReconnectPolicy{StallTimeout: 30 * time.Second, Jitter: &noJitter}.Merge(DefaultReconnectPolicy)
+
+	if err := policy.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if policy.StallTimeout != 30*time.Second {
+		t.Errorf("StallTimeout = %s, want %s", policy.StallTimeout, 30*time.Second)
+	}
+	if policy.InitialDelay != DefaultReconnectPolicy.InitialDelay {
+		t.Errorf("InitialDelay = %s, want %s", policy.InitialDelay, DefaultReconnectPolicy.InitialDelay)
+	}
+	if policy.Jitter == nil || *policy.Jitter != 0 {
+		t.Errorf("Jitter = %v, want 0", policy.Jitter)
+	}
+
+	if policy := (ReconnectPolicy{}).Merge(DefaultReconnectPolicy); *policy.Jitter != *DefaultReconnectPolicy.Jitter {
+		t.Errorf("Jitter = %g, want %g", *policy.Jitter, *DefaultReconnectPolicy.Jitter)
+	}
+}
+
+func TestReconnectPolicyValidate(t *testing.T) {
+	tooMuch := 1.5
+	tests := []ReconnectPolicy{
+		{InitialDelay: 0, MaxDelay: time.Minute, Multiplier: 2, StallTimeout: time.Second, FailbackInterval: time.Minute},
+		{InitialDelay: time.Minute, MaxDelay: time.Second, Multiplier: 2, StallTimeout: time.Second, FailbackInterval: time.Minute},
+		{InitialDelay: time.Second, MaxDelay: time.Minute, Multiplier: 0.5, StallTimeout: time.Second, FailbackInterval: time.Minute},
+		{InitialDelay: time.Second, MaxDelay: time.Minute, Multiplier: 2, Jitter: &tooMuch, StallTimeout: time.Second, FailbackInterval: time.Minute},
+		{InitialDelay: time.Second, MaxDelay: time.Minute, Multiplier: 2, FailbackInterval: time.Minute},
+		{InitialDelay: time.Second, MaxDelay: time.Minute, Multiplier: 2, StallTimeout: time.Second},
+	}
+	for i, policy := range tests {
+		if err := policy.Validate(); err == nil {
+			t.Errorf("%d: %+v: want error", i, policy)
+		}
+	}
+
+	if err := DefaultReconnectPolicy.Validate(); err != nil {
+		t.Error(err)
+	}
+}
@@ -14,46 +14,146 @@ func flag(c chan struct{}) {
 	}
 }
 
-func (p Player) watch(eventC <-chan *mpvipc.Event) {
+// watch mpv events until mpv exits and return the stream that should be playing.
+func (p *Player) watch(eventC <-chan *mpvipc.Event, exitC <-chan struct{}, stream string) string {
 	// modifiable by p.streamC
-	var stream string
-	var shouldPlay bool
+	shouldPlay := stream != ""
+	streams := p.streams(stream)
 
 	// modifiable by eventC
 	var isPlaying bool
 
+	wd := p.watchdog
+
 	// modifiable by eventC and pingT.C
-	pingD := 10 * time.Second
+	pingD := wd.policy.StallTimeout
 	pingT := time.NewTicker(pingD)
 
-	reloadStreamC := make(chan struct{}, 1)
+	reloadStreamC := p.reloadC
+
+	// Delayed reload from the reconnect policy, nil when there is none
+	var retryT *time.Timer
+	var retryC <-chan time.Time
+	retry := func(reason string) {
+		if retryT != nil {
+			return
+		}
+
+		d := wd.retry()
+		log.Printf("mpv.watch: %s: queuing reload in %s: %s", p.name, d.Truncate(time.Millisecond), reason)
+		retryT = time.NewTimer(d)
+		retryC = retryT.C
+	}
+	stopRetry := func() {
+		if retryT != nil {
+			retryT.Stop()
+			retryT, retryC = nil, nil
+		}
+	}
+	defer stopRetry()
+
+	// Fail over to the next URL or retry the current URL
+	failed := func(reason string) {
+		if wd.failover() {
+			log.Printf("mpv.watch: %s: failing over to fallback %d: %s", p.name, wd.stream, reason)
+			if err := canProbe(streams[0]); err != nil && wd.stream != 0 {
+				log.Printf("mpv.watch: %s: not failing back to primary: %s", p.name, err)
+			}
+			stopRetry()
+			p.updateStatus()
+			flag(reloadStreamC)
+			return
+		}
+
+		retry(reason)
+	}
+
+	// Probe the primary URL while a fallback URL is playing
+	failbackT := time.NewTicker(wd.policy.FailbackInterval)
+	defer failbackT.Stop()
+	probeC := make(chan error, 1)
+	probing := false
+
+	// Redraw label every second for the clock
+	labelT := time.NewTicker(time.Second)
+	defer labelT.Stop()
+
+	// Replay stream after respawn
+	if shouldPlay {
+		flag(reloadStreamC)
+	}
+	flag(p.labelC)
 
 	for {
 		select {
+		case <-retryC:
+			retryT, retryC = nil, nil
+			flag(reloadStreamC)
 		case <-reloadStreamC:
+			stopRetry()
+			pingT.Reset(pingD)
 			if shouldPlay {
 				log.Printf("mpv.watch: %s: reloading", p.name)
-				_, err := p.conn.Call("loadfile", stream)
+				_, err := p.call("loadfile", streams[wd.stream])
 				if err != nil {
 					log.Printf("mpv.watch: %s: reloading: %s", p.name, err)
 				}
+				wd.load()
 			} else {
 				log.Printf("mpv.watch: %s: stopping", p.name)
-				_, err := p.conn.Call("stop")
+				_, err := p.call("stop")
 				if err != nil {
 					log.Printf("mpv.watch: %s: stopping: %s", p.name, err)
 				}
+				wd.stop()
+			}
+			p.updateStatus()
+		case <-p.labelC:
+			if err := p.drawLabel(); err != nil {
+				log.Printf("mpv.watch: %s: label: %s", p.name, err)
+			}
+		case <-labelT.C:
+			// Clock and time since last frame
+			p.mu.Lock()
+			redraw := (p.label.Clock && !p.label.Hidden) || placeholder(p.status)
+			p.mu.Unlock()
+			if redraw {
+				flag(p.labelC)
 			}
 		case stream = <-p.streamC:
 			shouldPlay = stream != ""
+			streams = p.streams(stream)
+			wd.play(len(streams))
 			flag(reloadStreamC)
+			flag(p.labelC)
 		case <-pingT.C:
-			log.Printf("mpv.watch: %s: queuing reload: ping timeout", p.name)
-			flag(reloadStreamC)
+			if shouldPlay && retryT == nil {
+				wd.timeout()
+				p.updateStatus()
+				failed("ping timeout")
+			}
+		case <-failbackT.C:
+			if shouldPlay && wd.stream != 0 && !probing && canProbe(streams[0]) == nil {
+				probing = true
+				go func(stream string) { probeC <- probe(stream) }(streams[0])
+			}
+		case err := <-probeC:
+			probing = false
+			if err != nil {
+				log.Printf("mpv.watch: %s: probing primary: %s", p.name, err)
+			} else if shouldPlay && wd.stream != 0 {
+				log.Printf("mpv.watch: %s: failing back to primary", p.name)
+				wd.failback()
+				p.updateStatus()
+				flag(reloadStreamC)
+			}
+		case <-exitC:
+			pingT.Stop()
+			return stream
 		case event, ok := <-eventC:
 			if !ok {
 				pingT.Stop()
-				return
+				return stream
 			}
 
 			switch event.Name {
@@ -61,28 +161,52 @@ func (p Player) watch(eventC <-chan *mpvipc.Event) {
 				log.Printf("mpv.watch: %s: event: %s", p.name, event.Name)
 				isPlaying = false
 				pingT.Reset(pingD)
+				flag(p.labelC)
 			case "file-loaded":
 				log.Printf("mpv.watch: %s: event: %s", p.name, event.Name)
 				isPlaying = true
 				pingT.Reset(pingD)
+				wd.loaded(time.Now())
+				p.updateStatus()
+			case "playback-restart":
+				pingT.Reset(pingD)
+				wd.started(time.Now())
+				p.updateStatus()
 			case "end-file":
-				log.Printf("mpv.watch: %s: event: %s", p.name, event.Name)
+				log.Printf("mpv.watch: %s: event: %s: %s", p.name, event.Name, event.Reason)
 				isPlaying = false
 				pingT.Reset(pingD)
+				if shouldPlay && event.Reason != "stop" && event.Reason != "redirect" {
+					wd.ended(event.Reason)
+					p.updateStatus()
+					failed("end of file: " + event.Reason)
+				}
 			case "idle":
 				log.Printf("mpv.watch: %s: event: %s", p.name, event.Name)
 				isPlaying = false
 				pingT.Reset(pingD)
+				flag(p.labelC)
 			default:
 				if event.ID == event_demuxer_cache_time {
 					// Ping
 					pingT.Reset(pingD)
+					wd.frame(time.Now())
+					p.updateStatus()
 				} else if event.ID == event_demuxer_cache_idle && isPlaying && p.lowLatency && event.Data != nil && event.Data.(bool) {
 					// Reload stream if cache is idle and is a rtsp stream
-					log.Printf("mpv.watch: %s: queuing reload: no longer caching", p.name)
-					flag(reloadStreamC)
+					wd.idle()
+					p.updateStatus()
+					failed("no longer caching")
 				}
 			}
 		}
 	}
 }
+
+func (p *Player) call(arguments ...interface{}) (interface{}, error) {
+	p.mu.Lock()
+	conn := p.conn
+	p.mu.Unlock()
+
+	return conn.Call(arguments...)
+}
@@ -0,0 +1,161 @@
+package mpv
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+// watchdogFailures is how many loads in a row can fail before the stream is failed.
+const watchdogFailures = 3
+
+// watchdog tracks the state of a stream from mpv events and decides when to reload it, it does not talk to mpv.
+type watchdog struct {
+	policy ReconnectPolicy
+	random func() float64
+
+	status   xwm.PlayerStatus
+	played   bool
+	failures int
+	// attempt is the number of reloads since the stream last made progress
+	attempt int
+	// streams is the number of URLs of the stream including fallbacks
+	streams int
+	// stream is the index of the URL that is playing
+	stream int
+}
+
+func newWatchdog(policy ReconnectPolicy) *watchdog {
+	return &watchdog{
+		policy: policy,
+		random: rand.Float64,
+		status: xwm.PlayerStatus{State: xwm.StateStopped},
+	}
+}
+
+// load is called when the stream is loaded.
+func (w *watchdog) load() {
+	w.status.Started = time.Time{}
+	switch {
+	case w.failures >= watchdogFailures:
+		w.status.State = xwm.StateFailed
+	case w.played || w.failures > 0:
+		w.status.State = xwm.StateReconnecting
+	default:
+		w.status.State = xwm.StateConnecting
+	}
+}
+
+// play is called when the stream changes, streams is the number of URLs including fallbacks.
+func (w *watchdog) play(streams int) {
+	w.stop()
+	w.streams = streams
+}
+
+// stop is called when the stream is stopped.
+func (w *watchdog) stop() {
+	w.status = xwm.PlayerStatus{State: xwm.StateStopped}
+	w.played = false
+	w.failures = 0
+	w.attempt = 0
+	w.stream = 0
+}
+
+// failover switches to the next URL when the stream failed, it returns false when there is no other URL.
+func (w *watchdog) failover() bool {
+	if w.status.State != xwm.StateFailed || w.streams < 2 {
+		return false
+	}
+
+	w.stream = (w.stream + 1) % w.streams
+	w.status.Fallback = w.stream
+	w.failures = 0
+	w.attempt = 0
+
+	return true
+}
+
+// failback switches to the primary URL.
+func (w *watchdog) failback() {
+	w.stream = 0
+	w.status.Fallback = 0
+	w.failures = 0
+	w.attempt = 0
+}
+
+// loaded is called when the stream is opened.
+func (w *watchdog) loaded(now time.Time) {
+	w.status.State = xwm.StatePlaying
+	w.status.Reason = ""
+	w.status.LastFrame = now
+	w.played = true
+	w.failures = 0
+}
+
+// started is called when the stream shows its first frame after it is opened.
+func (w *watchdog) started(now time.Time) {
+	if w.status.Started.IsZero() {
+		w.status.Started = now
+	}
+	w.status.LastFrame = now
+}
+
+// frame is called when the stream makes progress.
+func (w *watchdog) frame(now time.Time) {
+	w.status.LastFrame = now
+	w.attempt = 0
+	if w.status.State == xwm.StateStalled {
+		w.status.State = xwm.StatePlaying
+		w.status.Reason = ""
+	}
+}
+
+// ended is called when the stream ends with the reason from mpv.
+func (w *watchdog) ended(reason string) {
+	w.fail(fmt.Sprintf("stream ended: %s", reason))
+}
+
+// timeout is called when the stream has not made progress for the stall timeout.
+func (w *watchdog) timeout() {
+	if w.status.State == xwm.StatePlaying {
+		w.status.State = xwm.StateStalled
+		w.status.Reason = fmt.Sprintf("no frames for %s", w.policy.StallTimeout)
+		return
+	}
+
+	w.fail(fmt.Sprintf("timed out after %s", w.policy.StallTimeout))
+}
+
+// idle is called when the low latency cache runs dry.
+func (w *watchdog) idle() {
+	if w.status.State == xwm.StatePlaying {
+		w.status.State = xwm.StateStalled
+		w.status.Reason = "cache is idle"
+	}
+}
+
+// retry returns how long to wait before reloading the stream, each call waits longer until the stream makes progress.
+func (w *watchdog) retry() time.Duration {
+	d := w.policy.delay(w.attempt, w.random())
+	w.attempt++
+
+	return d
+}
+
+// exited is called when mpv exits.
+func (w *watchdog) exited() {
+	w.status.State = xwm.StateFailed
+	w.status.Reason = "mpv exited"
+}
+
+func (w *watchdog) fail(reason string) {
+	w.failures++
+	w.status.Reason = reason
+	if w.failures >= watchdogFailures {
+		w.status.State = xwm.StateFailed
+	} else {
+		w.status.State = xwm.StateReconnecting
+	}
+}
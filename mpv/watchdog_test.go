@@ -0,0 +1,138 @@
+package mpv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ItsNotGoodName/x-ipcviewer/xwm"
+)
+
+func newTestWatchdog() *watchdog {
+	w := newWatchdog(DefaultReconnectPolicy)
+	w.random = func() float64 { return 0.5 }
+	return w
+}
+
+func checkState(t *testing.T, w *watchdog, state string) {
+	t.Helper()
+
+	if w.status.State != state {
+		t.Fatalf("state = %s (%s), want %s", w.status.State, w.status.Reason, state)
+	}
+}
+
+func TestWatchdogPlaying(t *testing.T) {
+	w := newTestWatchdog()
+	checkState(t, w, xwm.StateStopped)
+
+	w.load()
+	checkState(t, w, xwm.StateConnecting)
+
+	now := time.Now()
+	w.loaded(now)
+	checkState(t, w, xwm.StatePlaying)
+	if !w.status.LastFrame.Equal(now) {
+		t.Errorf("LastFrame = %s, want %s", w.status.LastFrame, now)
+	}
+
+	// No progress stalls a playing stream before it fails
+	w.timeout()
+	checkState(t, w, xwm.StateStalled)
+	w.frame(now.Add(time.Second))
+	checkState(t, w, xwm.StatePlaying)
+
+	w.idle()
+	checkState(t, w, xwm.StateStalled)
+	w.frame(now.Add(2 * time.Second))
+	checkState(t, w, xwm.StatePlaying)
+
+	// Reloading a stream that played is reconnecting
+	w.ended("error")
+	checkState(t, w, xwm.StateReconnecting)
+	w.load()
+	checkState(t, w, xwm.StateReconnecting)
+
+	w.stop()
+	checkState(t, w, xwm.StateStopped)
+	if !w.status.LastFrame.IsZero() {
+		t.Errorf("LastFrame = %s, want zero", w.status.LastFrame)
+	}
+}
+
+func TestWatchdogFailed(t *testing.T) {
+	w := newTestWatchdog()
+	w.load()
+
+	for i := 1; i < watchdogFailures; i++ {
+		w.timeout()
+		checkState(t, w, xwm.StateReconnecting)
+		w.load()
+		checkState(t, w, xwm.StateReconnecting)
+	}
+
+	w.ended("error")
+	checkState(t, w, xwm.StateFailed)
+	w.load()
+	checkState(t, w, xwm.StateFailed)
+
+	// Failed streams keep reloading and recover when they play
+	w.loaded(time.Now())
+	checkState(t, w, xwm.StatePlaying)
+	w.load()
+	checkState(t, w, xwm.StateReconnecting)
+
+	w.exited()
+	checkState(t, w, xwm.StateFailed)
+}
+
+func TestWatchdogRetry(t *testing.T) {
+	w := newTestWatchdog()
+	w.load()
+
+	for attempt := 0; attempt < 3; attempt++ {
+		if got, want := w.retry(), DefaultReconnectPolicy.delay(attempt, 0.5); got != want {
+			t.Errorf("retry %d = %s, want %s", attempt, got, want)
+		}
+	}
+
+	// Progress resets the backoff
+	w.loaded(time.Now())
+	w.frame(time.Now())
+	if got, want := w.retry(), DefaultReconnectPolicy.InitialDelay; got != want {
+		t.Errorf("retry after frame = %s, want %s", got, want)
+	}
+}
+
+func TestWatchdogFailover(t *testing.T) {
+	w := newTestWatchdog()
+	w.play(3)
+	w.load()
+
+	for i := 0; i < watchdogFailures; i++ {
+		if w.failover() {
+			t.Fatalf("failover after %d failures, want %d", i, watchdogFailures)
+		}
+		w.ended("error")
+	}
+
+	if !w.failover() || w.stream != 1 || w.status.Fallback != 1 {
+		t.Fatalf("failover: stream = %d, fallback = %d, want 1", w.stream, w.status.Fallback)
+	}
+	w.load()
+	checkState(t, w, xwm.StateConnecting)
+
+	w.failback()
+	if w.stream != 0 || w.status.Fallback != 0 {
+		t.Errorf("failback: stream = %d, fallback = %d, want 0", w.stream, w.status.Fallback)
+	}
+
+	// A stream without fallbacks stays failed
+	w.play(1)
+	w.load()
+	for i := 0; i < watchdogFailures; i++ {
+		w.ended("error")
+	}
+	if w.failover() {
+		t.Error("failover without fallbacks")
+	}
+}
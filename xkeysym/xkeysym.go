@@ -0,0 +1,132 @@
+// xkeysym maps X keysym names to keysyms, names are from X11/keysymdef.h
+package xkeysym
+
+import (
+	"strings"
+
+	"github.com/jezek/xgb/xproto"
+)
+
+var keysyms = map[string]xproto.Keysym{
+	"space":        0x0020,
+	"exclam":       0x0021,
+	"quotedbl":     0x0022,
+	"numbersign":   0x0023,
+	"dollar":       0x0024,
+	"percent":      0x0025,
+	"ampersand":    0x0026,
+	"apostrophe":   0x0027,
+	"parenleft":    0x0028,
+	"parenright":   0x0029,
+	"asterisk":     0x002a,
+	"plus":         0x002b,
+	"comma":        0x002c,
+	"minus":        0x002d,
+	"period":       0x002e,
+	"slash":        0x002f,
+	"colon":        0x003a,
+	"semicolon":    0x003b,
+	"less":         0x003c,
+	"equal":        0x003d,
+	"greater":      0x003e,
+	"question":     0x003f,
+	"at":           0x0040,
+	"bracketleft":  0x005b,
+	"backslash":    0x005c,
+	"bracketright": 0x005d,
+	"asciicircum":  0x005e,
+	"underscore":   0x005f,
+	"grave":        0x0060,
+	"braceleft":    0x007b,
+	"bar":          0x007c,
+	"braceright":   0x007d,
+	"asciitilde":   0x007e,
+	"BackSpace":    0xff08,
+	"Tab":          0xff09,
+	"Return":       0xff0d,
+	"Pause":        0xff13,
+	"Scroll_Lock":  0xff14,
+	"Escape":       0xff1b,
+	"Home":         0xff50,
+	"Left":         0xff51,
+	"Up":           0xff52,
+	"Right":        0xff53,
+	"Down":         0xff54,
+	"Prior":        0xff55,
+	"Page_Up":      0xff55,
+	"Next":         0xff56,
+	"Page_Down":    0xff56,
+	"End":          0xff57,
+	"Begin":        0xff58,
+	"Print":        0xff61,
+	"Insert":       0xff63,
+	"Menu":         0xff67,
+	"Num_Lock":     0xff7f,
+	"KP_Space":     0xff80,
+	"KP_Tab":       0xff89,
+	"KP_Enter":     0xff8d,
+	"KP_Home":      0xff95,
+	"KP_Left":      0xff96,
+	"KP_Up":        0xff97,
+	"KP_Right":     0xff98,
+	"KP_Down":      0xff99,
+	"KP_Prior":     0xff9a,
+	"KP_Page_Up":   0xff9a,
+	"KP_Next":      0xff9b,
+	"KP_Page_Down": 0xff9b,
+	"KP_End":       0xff9c,
+	"KP_Begin":     0xff9d,
+	"KP_Insert":    0xff9e,
+	"KP_Delete":    0xff9f,
+	"KP_Equal":     0xffbd,
+	"KP_Multiply":  0xffaa,
+	"KP_Add":       0xffab,
+	"KP_Separator": 0xffac,
+	"KP_Subtract":  0xffad,
+	"KP_Decimal":   0xffae,
+	"KP_Divide":    0xffaf,
+	"KP_0":         0xffb0,
+	"KP_1":         0xffb1,
+	"KP_2":         0xffb2,
+	"KP_3":         0xffb3,
+	"KP_4":         0xffb4,
+	"KP_5":         0xffb5,
+	"KP_6":         0xffb6,
+	"KP_7":         0xffb7,
+	"KP_8":         0xffb8,
+	"KP_9":         0xffb9,
+	"F1":           0xffbe,
+	"F2":           0xffbf,
+	"F3":           0xffc0,
+	"F4":           0xffc1,
+	"F5":           0xffc2,
+	"F6":           0xffc3,
+	"F7":           0xffc4,
+	"F8":           0xffc5,
+	"F9":           0xffc6,
+	"F10":          0xffc7,
+	"F11":          0xffc8,
+	"F12":          0xffc9,
+	"Delete":       0xffff,
+}
+
+// Keysym returns the keysym with the name.
+// Single printable ASCII characters (e.g. "q" or "1") are their own keysym.
+func Keysym(name string) (xproto.Keysym, bool) {
+	if len(name) == 1 && name[0] >= 0x20 && name[0] <= 0x7e {
+		return xproto.Keysym(name[0]), true
+	}
+
+	if keysym, ok := keysyms[name]; ok {
+		return keysym, true
+	}
+
+	// Fall back to case insensitive names (e.g. "escape" or "kp_1")
+	for n, keysym := range keysyms {
+		if strings.EqualFold(n, name) {
+			return keysym, true
+		}
+	}
+
+	return 0, false
+}
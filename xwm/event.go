@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/randr"
 	"github.com/jezek/xgb/xproto"
 )
 
@@ -11,11 +12,48 @@ type EventHandler interface {
 	ConfigureNotify(x *xgb.Conn, ev xproto.ConfigureNotifyEvent)
 	ButtonPress(x *xgb.Conn, ev xproto.ButtonPressEvent)
 	KeyPress(x *xgb.Conn, ev xproto.KeyPressEvent)
+	MappingNotify(x *xgb.Conn, ev xproto.MappingNotifyEvent)
+	Expose(x *xgb.Conn, ev xproto.ExposeEvent)
+	OutputChange(x *xgb.Conn)
 }
 
-func HandleEvent(x *xgb.Conn, eh EventHandler) {
+type event struct {
+	ev  xgb.Event
+	err xgb.Error
+}
+
+func waitForEvent(x *xgb.Conn, eventC chan<- event, doneC <-chan struct{}) {
 	for {
 		ev, err := x.WaitForEvent()
+		select {
+		case eventC <- event{ev: ev, err: err}:
+		case <-doneC:
+			return
+		}
+
+		if ev == nil && err == nil {
+			return
+		}
+	}
+}
+
+// HandleEvent handles X events and runs functions from the queue until the X connection is closed.
+func HandleEvent(x *xgb.Conn, eh EventHandler, q *Queue) {
+	defer q.close()
+
+	eventC := make(chan event)
+	go waitForEvent(x, eventC, q.doneC)
+
+	for {
+		var e event
+		select {
+		case fn := <-q.funcC:
+			fn()
+			continue
+		case e = <-eventC:
+		}
+
+		ev, err := e.ev, e.err
 		if ev == nil && err == nil {
 			log.Println("xwm.HandleEvent: exit: no event or error")
 			return
@@ -37,12 +75,21 @@ func HandleEvent(x *xgb.Conn, eh EventHandler) {
 		case xproto.KeyPressEvent:
 			log.Println("xwm.HandleEvent: key press event:", ev.Detail)
 
-			if ev.Detail == 24 { // q
-				log.Println("xwm.HandleEvent: exit: quit key pressed")
-				return
-			}
-
 			eh.KeyPress(x, ev)
+		case xproto.MappingNotifyEvent:
+			log.Println("xwm.HandleEvent: mapping notify event:", ev.Request)
+
+			eh.MappingNotify(x, ev)
+		case xproto.ExposeEvent:
+			eh.Expose(x, ev)
+		case randr.ScreenChangeNotifyEvent:
+			log.Println("xwm.HandleEvent: screen change notify event:", ev.Width, ev.Height)
+
+			eh.OutputChange(x)
+		case randr.NotifyEvent:
+			log.Println("xwm.HandleEvent: randr notify event:", ev.SubCode)
+
+			eh.OutputChange(x)
 		case xproto.DestroyNotifyEvent:
 			// Depending on the user's desktop environment (especially
 			// window manager), killing a window might close the
@@ -63,6 +110,9 @@ func HandleEvent(x *xgb.Conn, eh EventHandler) {
 			// For more information about closing windows while maintaining
 			// the X connection see
 			// https://github.com/jezek/xgbutil/blob/master/_examples/graceful-window-close/main.go
+			//
+			// Manager.Release clears the event mask before destroying its
+			// window, so only windows destroyed by someone else get here.
 			log.Println("xwm.HandleEvent: exit: destroy notify event")
 
 			return
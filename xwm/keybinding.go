@@ -0,0 +1,143 @@
+package xwm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+const (
+	// ActionFullscreen toggles fullscreen view of the window at Arg, starting from 1.
+	ActionFullscreen = "fullscreen"
+	// ActionLayout activates layout view, Arg is an optional layout name, next, or previous that is handled outside of Manager.
+	ActionLayout = "layout"
+	// ActionQuit exits the program.
+	ActionQuit = "quit"
+	// ActionNext shows the next window in fullscreen view.
+	ActionNext = "next"
+	// ActionPrevious shows the previous window in fullscreen view.
+	ActionPrevious = "previous"
+	// ActionMute toggles audio.
+	ActionMute = "mute"
+	// ActionPage activates layout view of the page at Arg, starting from 1, or the next or previous page.
+	ActionPage = "page"
+	// ActionDigit adds the digit at Arg to the window number that is being entered.
+	ActionDigit = "digit"
+	// ActionEnter confirms the window number that is being entered.
+	ActionEnter = "enter"
+	// ActionCancel cancels the window number that is being entered.
+	ActionCancel = "cancel"
+	// ActionLabels toggles the labels of windows.
+	ActionLabels = "labels"
+	// ActionFocus moves focus to the window in the direction at Arg in layout view.
+	// Left and up show the previous window and right and down show the next window in fullscreen view.
+	ActionFocus = "focus"
+)
+
+// Arguments for actions that cycle.
+const (
+	ArgNext     = "next"
+	ArgPrevious = "previous"
+)
+
+// Arguments for actions that take a direction.
+const (
+	ArgLeft  = "left"
+	ArgRight = "right"
+	ArgUp    = "up"
+	ArgDown  = "down"
+)
+
+// Action is something that can be done to a Manager, written as "name" or "name:arg".
+type Action struct {
+	Name string
+	Arg  string
+}
+
+func ParseAction(s string) (Action, error) {
+	name, arg, _ := strings.Cut(s, ":")
+	action := Action{Name: name, Arg: arg}
+
+	switch name {
+	case ActionFullscreen:
+		if i, err := strconv.Atoi(arg); err != nil || i < 1 {
+			return Action{}, fmt.Errorf("%s: invalid window number: %q", s, arg)
+		}
+	case ActionPage:
+		if i, err := strconv.Atoi(arg); (err != nil || i < 1) && arg != ArgNext && arg != ArgPrevious {
+			return Action{}, fmt.Errorf("%s: invalid page: %q", s, arg)
+		}
+	case ActionDigit:
+		if len(arg) != 1 || arg[0] < '0' || arg[0] > '9' {
+			return Action{}, fmt.Errorf("%s: invalid digit: %q", s, arg)
+		}
+	case ActionFocus:
+		if arg != ArgLeft && arg != ArgRight && arg != ArgUp && arg != ArgDown {
+			return Action{}, fmt.Errorf("%s: invalid direction: %q", s, arg)
+		}
+	case ActionLayout:
+	case ActionQuit, ActionNext, ActionPrevious, ActionMute, ActionEnter, ActionCancel, ActionLabels:
+		if arg != "" {
+			return Action{}, fmt.Errorf("%s: unexpected argument: %q", s, arg)
+		}
+	default:
+		return Action{}, fmt.Errorf("%s: unknown action", s)
+	}
+
+	return action, nil
+}
+
+func (a Action) String() string {
+	if a.Arg == "" {
+		return a.Name
+	}
+
+	return a.Name + ":" + a.Arg
+}
+
+type KeyBinding struct {
+	Keysym xproto.Keysym
+	Action Action
+}
+
+// Keymap maps keycodes to actions using the active keyboard mapping.
+type Keymap map[xproto.Keycode]Action
+
+// NewKeymap resolves key bindings to keycodes.
+// A keysym matches a keycode when it is the keycode's unshifted or shifted keysym, unshifted keysyms take priority.
+func NewKeymap(x *xgb.Conn, bindings []KeyBinding) (Keymap, error) {
+	setup := xproto.Setup(x)
+	count := byte(int(setup.MaxKeycode) - int(setup.MinKeycode) + 1)
+
+	reply, err := xproto.GetKeyboardMapping(x, setup.MinKeycode, count).Reply()
+	if err != nil {
+		return nil, err
+	}
+
+	perKeycode := int(reply.KeysymsPerKeycode)
+	columns := perKeycode
+	if columns > 2 {
+		columns = 2
+	}
+
+	keymap := make(Keymap)
+	for column := columns - 1; column >= 0; column-- {
+		for i := 0; i < int(count); i++ {
+			keysym := reply.Keysyms[i*perKeycode+column]
+			if keysym == 0 {
+				continue
+			}
+
+			for _, binding := range bindings {
+				if binding.Keysym == keysym {
+					keymap[setup.MinKeycode+xproto.Keycode(i)] = binding.Action
+				}
+			}
+		}
+	}
+
+	return keymap, nil
+}
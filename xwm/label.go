@@ -0,0 +1,20 @@
+package xwm
+
+// Label positions.
+const (
+	LabelTopLeft     = "top-left"
+	LabelTopRight    = "top-right"
+	LabelBottomLeft  = "bottom-left"
+	LabelBottomRight = "bottom-right"
+)
+
+// Label is text that a Player draws on top of its video.
+// The player adds its connection state to the text.
+type Label struct {
+	Text     string
+	Hidden   bool
+	Clock    bool
+	Position string
+	// FontSize is relative to a video height of 720 pixels.
+	FontSize int
+}
@@ -2,6 +2,7 @@ package xwm
 
 import (
 	"log"
+	"strconv"
 
 	"github.com/ItsNotGoodName/x-ipcviewer/mosaic"
 	"github.com/jezek/xgb"
@@ -12,16 +13,22 @@ import (
 type Manager struct {
 	wid               xproto.Window
 	fullscreenWid     xproto.Window
-	screen            *xproto.ScreenInfo
+	focusWid          xproto.Window
+	output            Output
 	mosaic            mosaic.Mosaic
 	width             uint16
 	height            uint16
 	windows           []Window
+	page              int
+	muted             bool
+	style             Style
+	prompt            *Prompt
+	labelsHidden      bool
 	lastButtonPressEv xproto.ButtonPressEvent
 }
 
-func NewManager(x *xgb.Conn, screen *xproto.ScreenInfo, cursor xproto.Cursor, m mosaic.Mosaic) (*Manager, error) {
-	width, height := screen.WidthInPixels, screen.HeightInPixels
+func NewManager(x *xgb.Conn, screen *xproto.ScreenInfo, output Output, cursor xproto.Cursor, m mosaic.Mosaic, style Style) (*Manager, error) {
+	width, height := output.Width, output.Height
 
 	// Generate root X window id
 	wid, err := xproto.NewWindowId(x)
@@ -29,14 +36,14 @@ func NewManager(x *xgb.Conn, screen *xproto.ScreenInfo, cursor xproto.Cursor, m
 		return nil, err
 	}
 
-	// Create root X window with black background and listen for resize, key presses, and button presses events
+	// Create root X window with the style's background and listen for resize, key presses, and button presses events
 	if err := xproto.CreateWindowChecked(x, screen.RootDepth,
 		wid, screen.Root,
-		0, 0, width, height, 0,
+		output.X, output.Y, width, height, 0,
 		xproto.WindowClassInputOutput, screen.RootVisual,
 		xproto.CwBackPixel|xproto.CwEventMask|xproto.CwCursor,
 		[]uint32{
-			0,
+			style.Background,
 			xproto.EventMaskStructureNotify |
 				xproto.EventMaskKeyPress |
 				xproto.EventMaskButtonPress,
@@ -53,79 +60,390 @@ func NewManager(x *xgb.Conn, screen *xproto.ScreenInfo, cursor xproto.Cursor, m
 
 	return &Manager{
 		wid:    wid,
-		screen: screen,
+		output: output,
 		mosaic: m,
 		width:  width,
 		height: height,
+		style:  style,
 	}, nil
 }
 
-func (m *Manager) AddWindows(x *xgb.Conn, windows []Window) {
-	m.windows = append(m.windows, windows...)
+// SetWindows replaces the windows and the layout of the mosaic used in layout view together, windows that are removed are not released.
+func (m *Manager) SetWindows(x *xgb.Conn, windows []Window, layout mosaic.Layout) {
+	m.windows = windows
+	m.mosaic.SetLayout(layout)
+	if m.Fullscreen() == -1 {
+		m.fullscreenWid = 0
+	}
+	if m.Focus() == -1 {
+		m.focusWid = 0
+	}
+	m.page = m.clampPage(m.page)
 
-	for i := range m.windows {
-		m.windows[i].Show(false, false)
+	m.show(x)
+	m.Update(x)
+}
+
+// SetLayout replaces the layout of the mosaic used in layout view.
+func (m *Manager) SetLayout(x *xgb.Conn, layout mosaic.Layout) {
+	m.mosaic.SetLayout(layout)
+	m.page = m.clampPage(m.page)
+
+	m.show(x)
+	m.Update(x)
+}
+
+// SetStyle changes the root X window's background and the borders of windows.
+func (m *Manager) SetStyle(x *xgb.Conn, style Style) {
+	if style == m.style {
+		return
+	}
+
+	m.style = style
+
+	xproto.ChangeWindowAttributes(x, m.wid, xproto.CwBackPixel, []uint32{style.Background})
+	xproto.ClearArea(x, false, m.wid, 0, 0, 0, 0)
+
+	m.show(x)
+	m.Update(x)
+}
+
+// Style returns the style, new windows should be created with it.
+func (m *Manager) Style() Style {
+	return m.style
+}
+
+// Pages returns the number of pages, windows that do not fit in the mosaic go to the next page.
+func (m *Manager) Pages() int {
+	count := m.mosaic.Count()
+	if count == 0 {
+		return 1
+	}
+
+	pages := (len(m.windows) + count - 1) / count
+	if pages == 0 {
+		return 1
 	}
 
+	return pages
+}
+
+// Page returns the current page, starting from 0.
+func (m *Manager) Page() int {
+	return m.page
+}
+
+func (m *Manager) clampPage(page int) int {
+	pages := m.Pages()
+	if page >= pages {
+		return pages - 1
+	}
+	if page < 0 {
+		return 0
+	}
+
+	return page
+}
+
+// SetPage activates layout view of the page, pages wrap around.
+func (m *Manager) SetPage(x *xgb.Conn, page int) {
+	pages := m.Pages()
+	page = ((page % pages) + pages) % pages
+	if page == m.page && m.fullscreenWid == 0 {
+		return
+	}
+
+	m.page = page
+	m.fullscreenWid = 0
+
+	m.show(x)
 	m.Update(x)
 }
 
+// onPage returns true when the window at index is on the current page.
+func (m *Manager) onPage(index int) bool {
+	count := m.mosaic.Count()
+	return index >= m.page*count && index < (m.page+1)*count
+}
+
+// ToggleFullscreen toggles fullscreen view of the window, wid 0 activates layout view.
 func (m *Manager) ToggleFullscreen(x *xgb.Conn, wid xproto.Window) {
-	if wid == 0 && m.fullscreenWid == wid {
+	if wid == m.fullscreenWid {
+		wid = 0
+	}
+
+	m.SetFullscreen(x, wid)
+}
+
+// SetFullscreen activates fullscreen view of the window, wid 0 activates layout view.
+// The fullscreen window is also focused.
+func (m *Manager) SetFullscreen(x *xgb.Conn, wid xproto.Window) {
+	if wid == m.fullscreenWid {
 		return
 	}
 
-	if wid == 0 || wid == m.fullscreenWid {
-		// Normal
-		m.fullscreenWid = 0
+	m.fullscreenWid = wid
+	if wid != 0 {
+		m.focusWid = wid
+	}
 
-		for _, window := range m.windows {
-			window.Show(false, false)
+	m.show(x)
+	m.Update(x)
+}
+
+// SetFocus focuses the window, only the focused window has audio in layout view.
+// Wid 0 removes focus.
+func (m *Manager) SetFocus(x *xgb.Conn, wid xproto.Window) {
+	if wid == m.focusWid {
+		return
+	}
+
+	m.focusWid = wid
+
+	m.show(x)
+	m.Update(x)
+}
+
+// MoveFocus focuses the nearest window on the current page in the direction of the focused window.
+// The first window on the page is focused when no window on the page is focused.
+// In fullscreen view, left and up show the previous window and right and down show the next window.
+func (m *Manager) MoveFocus(x *xgb.Conn, direction string) {
+	if m.fullscreenWid != 0 {
+		if direction == ArgLeft || direction == ArgUp {
+			m.Previous(x)
+		} else {
+			m.Next(x)
+		}
+		return
+	}
+	if len(m.windows) == 0 {
+		return
+	}
+
+	mosaicWindows := m.mosaic.Windows(m.width, m.height)
+	offset := m.page * len(mosaicWindows)
+	count := len(m.windows) - offset
+	if count > len(mosaicWindows) {
+		count = len(mosaicWindows)
+	}
+	if count <= 0 {
+		return
+	}
+
+	focus := m.Focus() - offset
+	if focus < 0 || focus >= count {
+		m.SetFocus(x, m.windows[offset].wid)
+		return
+	}
+
+	if next := nearest(mosaicWindows[:count], focus, direction); next != -1 {
+		m.SetFocus(x, m.windows[offset+next].wid)
+	}
+}
+
+// nearest returns the index of the window closest to the window at from in the direction or -1.
+// Only windows whose center is past the edge of the window at from are considered.
+// Distance on the other axis counts double so windows in the same row or column are preferred.
+func nearest(wins []mosaic.Window, from int, direction string) int {
+	center := func(w mosaic.Window) (int, int) { return int(w.X) + int(w.W)/2, int(w.Y) + int(w.H)/2 }
+	abs := func(i int) int {
+		if i < 0 {
+			return -i
+		}
+		return i
+	}
+
+	fx, fy := center(wins[from])
+	best, bestScore := -1, 0
+	for i := range wins {
+		if i == from {
+			continue
+		}
+
+		x, y := center(wins[i])
+		var distance, offAxis int
+		switch direction {
+		case ArgLeft:
+			distance, offAxis = fx-x, abs(y-fy)
+		case ArgRight:
+			distance, offAxis = x-fx, abs(y-fy)
+		case ArgUp:
+			distance, offAxis = fy-y, abs(x-fx)
+		case ArgDown:
+			distance, offAxis = y-fy, abs(x-fx)
+		default:
+			return -1
+		}
+		if distance <= 0 || !beyond(wins[from], x, y, direction) {
+			continue
+		}
+
+		if score := distance + 2*offAxis; best == -1 || score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	return best
+}
+
+// beyond returns true when the point is past the edge of the window in the direction.
+func beyond(w mosaic.Window, x, y int, direction string) bool {
+	switch direction {
+	case ArgLeft:
+		return x < int(w.X)
+	case ArgRight:
+		return x >= int(w.X)+int(w.W)
+	case ArgUp:
+		return y < int(w.Y)
+	case ArgDown:
+		return y >= int(w.Y)+int(w.H)
+	}
+
+	return false
+}
+
+func (m *Manager) label() Label {
+	label := m.style.Label
+	label.Hidden = label.Hidden || m.labelsHidden
+
+	return label
+}
+
+// ToggleLabels shows or hides the labels of windows.
+func (m *Manager) ToggleLabels(x *xgb.Conn) {
+	m.labelsHidden = !m.labelsHidden
+
+	m.show(x)
+}
+
+// audible returns true when the window should have audio in the current view.
+func (m *Manager) audible(wid xproto.Window) bool {
+	if m.muted {
+		return false
+	}
+	if m.fullscreenWid != 0 {
+		return wid == m.fullscreenWid
+	}
+
+	return wid == m.focusWid
+}
+
+// show maps, unmaps, plays, stops, mutes, and unmutes windows for the current view.
+func (m *Manager) show(x *xgb.Conn) {
+	if m.fullscreenWid == 0 {
+		// Normal
+		for i, window := range m.windows {
+			if m.onPage(i) {
+				xproto.MapWindow(x, window.wid)
+				window.Show(m.audible(window.wid), false)
+				window.SetLabel(m.label(), false)
+			} else {
+				xproto.UnmapWindow(x, window.wid)
+				window.Hide()
+			}
 		}
 	} else {
 		// Fullscreen
-		m.fullscreenWid = wid
-
 		for _, window := range m.windows {
-			if window.wid == wid {
+			if window.wid == m.fullscreenWid {
 				// Move fullscreen window to top of stack
 				if err := xproto.ConfigureWindowChecked(x, window.wid, xproto.ConfigWindowStackMode, []uint32{0}).Check(); err != nil {
-					log.Printf("xwm.Manager.ToggleFullscreen: window %d: stack: %s\n", window.wid, err)
+					log.Printf("xwm.Manager.show: window %d: stack: %s\n", window.wid, err)
+				}
+				xproto.MapWindow(x, window.wid)
+				window.Show(m.audible(window.wid), true)
+				window.SetLabel(m.label(), true)
+				if m.prompt != nil {
+					m.prompt.Raise(x)
 				}
-				window.Show(true, true)
 			} else {
+				xproto.UnmapWindow(x, window.wid)
 				window.Hide()
 			}
 		}
 	}
-
-	m.Update(x)
 }
 
-// Update X windows' x, y, width, and height.
+// Update X windows' x, y, width, height, border width, and border color.
 func (m *Manager) Update(x *xgb.Conn) {
 	if m.fullscreenWid == 0 {
 		// Normal
 		mosaicWindows := m.mosaic.Windows(m.width, m.height)
-		windowsLength, mosaicWindowsLength := len(m.windows), len(mosaicWindows)
+		offset := m.page * len(mosaicWindows)
+		windowsLength, mosaicWindowsLength := len(m.windows)-offset, len(mosaicWindows)
 		for i := 0; i < windowsLength && i < mosaicWindowsLength; i++ {
-			window := m.windows[i]
+			window := m.windows[offset+i]
+			mw := mosaicWindows[i]
 
-			if err := xproto.ConfigureWindowChecked(x, window.wid, xproto.ConfigWindowX|xproto.ConfigWindowY|xproto.ConfigWindowWidth|xproto.ConfigWindowHeight, []uint32{uint32(mosaicWindows[i].X), uint32(mosaicWindows[i].Y), uint32(mosaicWindows[i].W), uint32(mosaicWindows[i].H)}).Check(); err != nil {
+			// Focused window's wider border is taken from inside its rectangle
+			borderWidth, borderColor := m.style.BorderWidth, m.style.BorderColor
+			if window.wid == m.focusWid && m.style.FocusBorderWidth > 0 {
+				borderColor = m.style.FocusColor
+				if extra := 2 * (m.style.FocusBorderWidth - borderWidth); m.style.FocusBorderWidth > borderWidth && mw.W > extra && mw.H > extra {
+					mw.W -= extra
+					mw.H -= extra
+					borderWidth = m.style.FocusBorderWidth
+				}
+			}
+
+			// Restore the border removed by fullscreen view
+			xproto.ChangeWindowAttributes(x, window.wid, xproto.CwBorderPixel, []uint32{borderColor})
+			if err := xproto.ConfigureWindowChecked(x, window.wid, xproto.ConfigWindowX|xproto.ConfigWindowY|xproto.ConfigWindowWidth|xproto.ConfigWindowHeight|xproto.ConfigWindowBorderWidth, []uint32{uint32(mw.X), uint32(mw.Y), uint32(mw.W), uint32(mw.H), uint32(borderWidth)}).Check(); err != nil {
 				log.Printf("xwm.Manager.Update: window %d: %s\n", window.wid, err)
 			}
+			window.resize(x, mw.W, mw.H)
 		}
 	} else {
 		// Fullscreen
-		if err := xproto.ConfigureWindowChecked(x, m.fullscreenWid, xproto.ConfigWindowX|xproto.ConfigWindowY|xproto.ConfigWindowWidth|xproto.ConfigWindowHeight, []uint32{uint32(0), uint32(0), uint32(m.width), uint32(m.height)}).Check(); err != nil {
+		// Fullscreen window has no border
+		if err := xproto.ConfigureWindowChecked(x, m.fullscreenWid, xproto.ConfigWindowX|xproto.ConfigWindowY|xproto.ConfigWindowWidth|xproto.ConfigWindowHeight|xproto.ConfigWindowBorderWidth, []uint32{uint32(0), uint32(0), uint32(m.width), uint32(m.height), 0}).Check(); err != nil {
 			log.Printf("xwm.Manager.Update: window %d: %s\n", m.fullscreenWid, err)
 		}
+		if i := m.Fullscreen(); i != -1 {
+			m.windows[i].resize(x, m.width, m.height)
+		}
 	}
 }
 
-func (m *Manager) Release() {
+func (m *Manager) Release(x *xgb.Conn) {
 	for _, window := range m.windows {
-		window.Release()
+		window.Release(x)
+	}
+
+	if m.prompt != nil {
+		m.prompt.Release(x)
+	}
+
+	// HandleEvent exits on DestroyNotify, stop selecting it so releasing
+	// the manager of an unplugged output does not close the viewer
+	xproto.ChangeWindowAttributes(x, m.wid, xproto.CwEventMask, []uint32{xproto.EventMaskNoEvent})
+	xproto.DestroyWindow(x, m.wid)
+}
+
+// ShowPrompt shows the text on top of the windows.
+func (m *Manager) ShowPrompt(x *xgb.Conn, text string) {
+	if m.prompt == nil {
+		prompt, err := NewPrompt(x, m.wid)
+		if err != nil {
+			log.Printf("xwm.Manager.ShowPrompt: window %d: %s\n", m.wid, err)
+			return
+		}
+
+		m.prompt = prompt
+	}
+
+	m.prompt.Show(x, text)
+}
+
+func (m *Manager) HidePrompt(x *xgb.Conn) {
+	if m.prompt != nil {
+		m.prompt.Hide(x)
+	}
+}
+
+func (m *Manager) Expose(x *xgb.Conn, ev xproto.ExposeEvent) {
+	if m.prompt != nil {
+		m.prompt.Expose(x, ev)
 	}
 }
 
@@ -137,18 +455,113 @@ func (m *Manager) ConfigureNotify(x *xgb.Conn, ev xproto.ConfigureNotifyEvent) {
 	}
 }
 
-func (m *Manager) KeyPress(x *xgb.Conn, ev xproto.KeyPressEvent) {
-	// Keypad 1 - 9
-	if ev.Detail >= 10 && ev.Detail <= 18 {
-		windowsLen := len(m.windows)
-		i := int(ev.Detail - 10)
-		if i < windowsLen {
-			m.ToggleFullscreen(x, m.windows[i].wid)
+// SetOutput moves and resizes the root X window to the output.
+func (m *Manager) SetOutput(x *xgb.Conn, output Output) {
+	if output == m.output {
+		return
+	}
+
+	if err := xproto.ConfigureWindowChecked(x, m.wid, xproto.ConfigWindowX|xproto.ConfigWindowY|xproto.ConfigWindowWidth|xproto.ConfigWindowHeight, []uint32{uint32(output.X), uint32(output.Y), uint32(output.Width), uint32(output.Height)}).Check(); err != nil {
+		log.Printf("xwm.Manager.SetOutput: window %d: %s\n", m.wid, err)
+		return
+	}
+
+	m.output = output
+	m.width = output.Width
+	m.height = output.Height
+	m.Update(x)
+}
+
+// Do the action, actions that are not for a Manager are ignored.
+func (m *Manager) Do(x *xgb.Conn, action Action) {
+	switch action.Name {
+	case ActionFullscreen:
+		i, err := strconv.Atoi(action.Arg)
+		if err != nil {
+			log.Printf("xwm.Manager.Do: %s: %s\n", action, err)
+			return
 		}
 
+		if i > 0 && i <= len(m.windows) {
+			m.ToggleFullscreen(x, m.windows[i-1].wid)
+		}
+	case ActionLayout:
+		m.SetFullscreen(x, 0)
+	case ActionNext:
+		m.Next(x)
+	case ActionPrevious:
+		m.Previous(x)
+	case ActionMute:
+		m.ToggleMute()
+	case ActionFocus:
+		m.MoveFocus(x, action.Arg)
+	case ActionLabels:
+		m.ToggleLabels(x)
+	case ActionPage:
+		switch action.Arg {
+		case ArgNext:
+			m.SetPage(x, m.page+1)
+		case ArgPrevious:
+			m.SetPage(x, m.page-1)
+		default:
+			i, err := strconv.Atoi(action.Arg)
+			if err != nil {
+				log.Printf("xwm.Manager.Do: %s: %s\n", action, err)
+				return
+			}
+
+			if i > 0 && i <= m.Pages() {
+				m.SetPage(x, i-1)
+			}
+		}
+	}
+}
+
+// Next shows the window after the fullscreen window in fullscreen view.
+// In layout view, the focused window or the first window is shown.
+func (m *Manager) Next(x *xgb.Conn) {
+	m.step(x, 1)
+}
+
+// Previous shows the window before the fullscreen window in fullscreen view.
+// In layout view, the focused window or the last window is shown.
+func (m *Manager) Previous(x *xgb.Conn) {
+	m.step(x, -1)
+}
+
+func (m *Manager) step(x *xgb.Conn, delta int) {
+	count := len(m.windows)
+	if count == 0 {
 		return
-	} else if ev.Detail == 19 { // 0
-		m.ToggleFullscreen(x, 0)
+	}
+
+	var i int
+	if fullscreen := m.Fullscreen(); fullscreen != -1 {
+		i = ((fullscreen+delta)%count + count) % count
+	} else if focus := m.Focus(); focus != -1 {
+		i = focus
+	} else if delta < 0 {
+		i = count - 1
+	}
+
+	m.SetFullscreen(x, m.windows[i].wid)
+}
+
+// ToggleMute toggles audio of the fullscreen or focused window.
+func (m *Manager) ToggleMute() {
+	m.SetMute(!m.muted)
+}
+
+// SetMute sets audio of the fullscreen or focused window.
+func (m *Manager) SetMute(mute bool) {
+	m.muted = mute
+
+	for i, window := range m.windows {
+		if m.fullscreenWid != 0 && window.wid == m.fullscreenWid {
+			window.Show(m.audible(window.wid), true)
+		} else if m.fullscreenWid == 0 && window.wid == m.focusWid && m.onPage(i) {
+			window.Show(m.audible(window.wid), false)
+		}
 	}
 }
 
@@ -166,10 +579,57 @@ func (m *Manager) buttonPress(x *xgb.Conn, ev xproto.ButtonPressEvent, double bo
 	if ev.Detail == 1 {
 		if double {
 			m.ToggleFullscreen(x, ev.Child)
+		} else if ev.Child != 0 {
+			m.SetFocus(x, ev.Child)
 		}
 	}
 }
 
+// Reload the stream of the window at index.
+func (m *Manager) Reload(index int) {
+	if index >= 0 && index < len(m.windows) {
+		m.windows[index].Reload()
+	}
+}
+
+// Fullscreen returns the index of the fullscreen window or -1 when in layout view.
+func (m *Manager) Fullscreen() int {
+	for i := range m.windows {
+		if m.windows[i].wid == m.fullscreenWid {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Focus returns the index of the focused window or -1 when no window is focused.
+func (m *Manager) Focus() int {
+	if m.focusWid == 0 {
+		return -1
+	}
+
+	for i := range m.windows {
+		if m.windows[i].wid == m.focusWid {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (m *Manager) Muted() bool {
+	return m.muted
+}
+
+func (m *Manager) Windows() []Window {
+	return m.windows
+}
+
 func (m *Manager) WID() xproto.Window {
 	return m.wid
 }
+
+func (m *Manager) Output() Output {
+	return m.output
+}
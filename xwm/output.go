@@ -0,0 +1,111 @@
+package xwm
+
+import (
+	"log"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/randr"
+	"github.com/jezek/xgb/xproto"
+)
+
+// Output is a monitor that is connected to the X screen.
+type Output struct {
+	Name    string
+	Primary bool
+	X       int16
+	Y       int16
+	Width   uint16
+	Height  uint16
+}
+
+// ScreenOutput returns an output that covers the whole screen.
+func ScreenOutput(screen *xproto.ScreenInfo) Output {
+	return Output{
+		Primary: true,
+		Width:   screen.WidthInPixels,
+		Height:  screen.HeightInPixels,
+	}
+}
+
+// InitOutputs enables RandR and listens for screen and output changes on the screen's root window.
+func InitOutputs(x *xgb.Conn, screen *xproto.ScreenInfo) error {
+	if err := randr.Init(x); err != nil {
+		return err
+	}
+
+	return randr.SelectInputChecked(x, screen.Root, randr.NotifyMaskScreenChange|randr.NotifyMaskCrtcChange|randr.NotifyMaskOutputChange).Check()
+}
+
+// Outputs returns the connected outputs of the screen using RandR.
+// The whole screen is returned as a single output when RandR is not available.
+func Outputs(x *xgb.Conn, screen *xproto.ScreenInfo) []Output {
+	x.ExtLock.RLock()
+	_, ok := x.Extensions["RANDR"]
+	x.ExtLock.RUnlock()
+	if !ok {
+		return []Output{ScreenOutput(screen)}
+	}
+
+	outputs, err := randrOutputs(x, screen)
+	if err != nil {
+		log.Println("xwm.Outputs: falling back to screen:", err)
+		return []Output{ScreenOutput(screen)}
+	}
+	if len(outputs) == 0 {
+		log.Println("xwm.Outputs: falling back to screen: no connected outputs")
+		return []Output{ScreenOutput(screen)}
+	}
+
+	return outputs
+}
+
+func randrOutputs(x *xgb.Conn, screen *xproto.ScreenInfo) ([]Output, error) {
+	resources, err := randr.GetScreenResourcesCurrent(x, screen.Root).Reply()
+	if err != nil {
+		return nil, err
+	}
+
+	var primary randr.Output
+	if reply, err := randr.GetOutputPrimary(x, screen.Root).Reply(); err == nil {
+		primary = reply.Output
+	}
+
+	var outputs []Output
+	for _, o := range resources.Outputs {
+		info, err := randr.GetOutputInfo(x, o, resources.ConfigTimestamp).Reply()
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip disconnected and disabled outputs
+		if info.Connection != randr.ConnectionConnected || info.Crtc == 0 {
+			continue
+		}
+
+		crtc, err := randr.GetCrtcInfo(x, info.Crtc, resources.ConfigTimestamp).Reply()
+		if err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, Output{
+			Name:    string(info.Name),
+			Primary: o == primary,
+			X:       crtc.X,
+			Y:       crtc.Y,
+			Width:   crtc.Width,
+			Height:  crtc.Height,
+		})
+	}
+
+	// Use first output as primary when RandR does not have a usable one
+	for _, output := range outputs {
+		if output.Primary {
+			return outputs, nil
+		}
+	}
+	if len(outputs) > 0 {
+		outputs[0].Primary = true
+	}
+
+	return outputs, nil
+}
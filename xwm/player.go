@@ -1,6 +1,33 @@
 package xwm
 
-import "github.com/jezek/xgb/xproto"
+import (
+	"time"
+
+	"github.com/jezek/xgb/xproto"
+)
+
+// Player states.
+const (
+	StateStopped      = "stopped"
+	StateConnecting   = "connecting"
+	StatePlaying      = "playing"
+	StateStalled      = "stalled"
+	StateReconnecting = "reconnecting"
+	StateFailed       = "failed"
+)
+
+// PlayerStatus is what a Player's watchdog knows about its stream.
+type PlayerStatus struct {
+	State string
+	// Reason is why the stream is not playing.
+	Reason string
+	// LastFrame is when the stream last made progress, zero when it never did.
+	LastFrame time.Time
+	// Started is when the stream showed its first frame since it was last loaded, zero until then.
+	Started time.Time
+	// Fallback is the index of the fallback URL that is playing, 0 is the primary URL.
+	Fallback int
+}
 
 // Player handles displaying a stream to a X window.
 type Player interface {
@@ -10,6 +37,14 @@ type Player interface {
 	Play(stream string) error
 	// Stop playing current stream.
 	Stop() error
+	// Reload current stream.
+	Reload() error
+	// SetLabel replaces the label drawn on top of the video.
+	SetLabel(label Label) error
+	// Err returns why the player is not working.
+	Err() error
+	// Status returns the state of the stream.
+	Status() PlayerStatus
 	// Release held resources.
 	Release()
 }
@@ -21,6 +56,7 @@ type PlayerCache struct {
 	player Player
 	muted  bool
 	stream string
+	label  Label
 }
 
 func NewPlayerCache(player Player) *PlayerCache {
@@ -69,6 +105,36 @@ func (pc *PlayerCache) Stop() error {
 	return nil
 }
 
+func (pc *PlayerCache) Reload() error {
+	if pc.stream == "" {
+		return nil
+	}
+
+	return pc.player.Reload()
+}
+
+func (pc *PlayerCache) SetLabel(label Label) error {
+	if label == pc.label {
+		return nil
+	}
+
+	if err := pc.player.SetLabel(label); err != nil {
+		return err
+	}
+
+	pc.label = label
+
+	return nil
+}
+
+func (pc *PlayerCache) Err() error {
+	return pc.player.Err()
+}
+
+func (pc *PlayerCache) Status() PlayerStatus {
+	return pc.player.Status()
+}
+
 func (pc *PlayerCache) Release() {
 	pc.player.Release()
 }
@@ -0,0 +1,139 @@
+package xwm
+
+import (
+	"log"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// promptFonts are X core fonts that are tried in order, fixed should always exist.
+var promptFonts = []string{
+	"-*-*-bold-r-normal-*-34-*-*-*-*-*-iso8859-1",
+	"-*-fixed-bold-r-normal-*-18-*-*-*-*-*-iso8859-1",
+	"fixed",
+}
+
+const (
+	promptPadding = 8
+	promptMargin  = 16
+)
+
+// Prompt is white text in a black box in the top left corner of a parent X window.
+type Prompt struct {
+	wid  xproto.Window
+	gc   xproto.Gcontext
+	font xproto.Font
+	text string
+}
+
+func NewPrompt(x *xgb.Conn, parent xproto.Window) (*Prompt, error) {
+	font, err := xproto.NewFontId(x)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, name := range promptFonts {
+		if err = xproto.OpenFontChecked(x, font, uint16(len(name)), name).Check(); err == nil {
+			break
+		}
+		if i == len(promptFonts)-1 {
+			return nil, err
+		}
+	}
+
+	wid, err := xproto.NewWindowId(x)
+	if err != nil {
+		xproto.CloseFont(x, font)
+		return nil, err
+	}
+
+	if err := xproto.CreateWindowChecked(x, xproto.WindowClassCopyFromParent,
+		wid, parent,
+		promptMargin, promptMargin, 1, 1, 1,
+		xproto.WindowClassInputOutput, xproto.WindowClassCopyFromParent,
+		xproto.CwBackPixel|xproto.CwBorderPixel|xproto.CwEventMask,
+		[]uint32{0, 0xffffff, xproto.EventMaskExposure}).Check(); err != nil {
+		xproto.CloseFont(x, font)
+		return nil, err
+	}
+
+	gc, err := xproto.NewGcontextId(x)
+	if err != nil {
+		xproto.DestroyWindow(x, wid)
+		xproto.CloseFont(x, font)
+		return nil, err
+	}
+
+	if err := xproto.CreateGCChecked(x, gc, xproto.Drawable(wid), xproto.GcForeground|xproto.GcBackground|xproto.GcFont, []uint32{0xffffff, 0, uint32(font)}).Check(); err != nil {
+		xproto.DestroyWindow(x, wid)
+		xproto.CloseFont(x, font)
+		return nil, err
+	}
+
+	return &Prompt{
+		wid:  wid,
+		gc:   gc,
+		font: font,
+	}, nil
+}
+
+// Show the text on top of sibling X windows.
+func (p *Prompt) Show(x *xgb.Conn, text string) {
+	if len(text) > 255 {
+		text = text[:255]
+	}
+	p.text = text
+
+	chars := make([]xproto.Char2b, len(text))
+	for i := range text {
+		chars[i] = xproto.Char2b{Byte2: text[i]}
+	}
+	extents, err := xproto.QueryTextExtents(x, xproto.Fontable(p.font), chars, uint16(len(chars))).Reply()
+	if err != nil {
+		log.Println("xwm.Prompt.Show:", err)
+		return
+	}
+
+	width := uint32(extents.OverallWidth) + 2*promptPadding
+	height := uint32(extents.FontAscent) + uint32(extents.FontDescent) + 2*promptPadding
+	xproto.ConfigureWindow(x, p.wid, xproto.ConfigWindowWidth|xproto.ConfigWindowHeight|xproto.ConfigWindowStackMode, []uint32{width, height, xproto.StackModeAbove})
+	xproto.MapWindow(x, p.wid)
+	xproto.ClearArea(x, false, p.wid, 0, 0, 0, 0)
+	p.draw(x, extents.FontAscent)
+}
+
+// Raise the prompt above sibling X windows.
+func (p *Prompt) Raise(x *xgb.Conn) {
+	xproto.ConfigureWindow(x, p.wid, xproto.ConfigWindowStackMode, []uint32{xproto.StackModeAbove})
+}
+
+func (p *Prompt) Hide(x *xgb.Conn) {
+	p.text = ""
+	xproto.UnmapWindow(x, p.wid)
+}
+
+// Expose redraws the text when the prompt's X window is exposed.
+func (p *Prompt) Expose(x *xgb.Conn, ev xproto.ExposeEvent) {
+	if ev.Window != p.wid || ev.Count != 0 || p.text == "" {
+		return
+	}
+
+	reply, err := xproto.QueryFont(x, xproto.Fontable(p.font)).Reply()
+	if err != nil {
+		log.Println("xwm.Prompt.Expose:", err)
+		return
+	}
+
+	p.draw(x, reply.FontAscent)
+}
+
+func (p *Prompt) draw(x *xgb.Conn, ascent int16) {
+	xproto.ImageText8(x, byte(len(p.text)), xproto.Drawable(p.wid), p.gc, promptPadding, promptPadding+ascent, p.text)
+}
+
+func (p *Prompt) Release(x *xgb.Conn) {
+	xproto.FreeGC(x, p.gc)
+	xproto.DestroyWindow(x, p.wid)
+	xproto.CloseFont(x, p.font)
+}
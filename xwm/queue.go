@@ -0,0 +1,46 @@
+package xwm
+
+import "time"
+
+// Queue runs functions inside HandleEvent so they do not race with event handlers.
+type Queue struct {
+	funcC chan func()
+	doneC chan struct{}
+}
+
+func NewQueue() *Queue {
+	return &Queue{
+		funcC: make(chan func()),
+		doneC: make(chan struct{}),
+	}
+}
+
+// Do runs fn inside HandleEvent and waits for it to return.
+// It returns false when HandleEvent has exited and fn was not run.
+func (q *Queue) Do(fn func()) bool {
+	doneC := make(chan struct{})
+	select {
+	case q.funcC <- func() {
+		fn()
+		close(doneC)
+	}:
+	case <-q.doneC:
+		return false
+	}
+
+	<-doneC
+
+	return true
+}
+
+// AfterFunc runs fn inside HandleEvent after the duration.
+// fn might still run after the timer is stopped.
+func (q *Queue) AfterFunc(d time.Duration, fn func()) *time.Timer {
+	return time.AfterFunc(d, func() {
+		q.Do(fn)
+	})
+}
+
+func (q *Queue) close() {
+	close(q.doneC)
+}
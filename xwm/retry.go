@@ -0,0 +1,190 @@
+package xwm
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v3"
+	"github.com/jezek/xgb/xproto"
+)
+
+var errPlayerStarting = errors.New("starting")
+
+// RetryPlayer creates a player in the background and retries with backoff until it succeeds.
+// Calls made before the player is created are applied once it is created.
+type RetryPlayer struct {
+	name   string
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	player Player
+	err    error
+	muted  bool
+	stream string
+	label  Label
+}
+
+func NewRetryPlayer(name string, wid xproto.Window, factory PlayerFactory) *RetryPlayer {
+	ctx, cancel := context.WithCancel(context.Background())
+	rp := &RetryPlayer{
+		name:   name,
+		cancel: cancel,
+		err:    errPlayerStarting,
+	}
+
+	go rp.create(ctx, wid, factory)
+
+	return rp
+}
+
+func (rp *RetryPlayer) create(ctx context.Context, wid xproto.Window, factory PlayerFactory) {
+	var player Player
+	err := retry.Do(func() error {
+		var err error
+		player, err = factory(wid)
+		return err
+	},
+		retry.Context(ctx),
+		retry.Attempts(^uint(0)),
+		retry.LastErrorOnly(true),
+		retry.Delay(time.Second),
+		retry.MaxDelay(time.Minute),
+		retry.DelayType(retry.BackOffDelay),
+		retry.OnRetry(func(n uint, err error) {
+			log.Printf("xwm.RetryPlayer.create: %s: attempt %d: %s", rp.name, n+1, err)
+
+			rp.mu.Lock()
+			rp.err = err
+			rp.mu.Unlock()
+		}),
+	)
+	if err != nil {
+		return
+	}
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	// Released while creating
+	if ctx.Err() != nil {
+		player.Release()
+		return
+	}
+
+	log.Printf("xwm.RetryPlayer.create: %s: created", rp.name)
+
+	if err := player.Mute(rp.muted); err != nil {
+		log.Printf("xwm.RetryPlayer.create: %s: Mute: %s", rp.name, err)
+	}
+	if err := player.SetLabel(rp.label); err != nil {
+		log.Printf("xwm.RetryPlayer.create: %s: SetLabel: %s", rp.name, err)
+	}
+	if rp.stream != "" {
+		if err := player.Play(rp.stream); err != nil {
+			log.Printf("xwm.RetryPlayer.create: %s: Play: %s", rp.name, err)
+		}
+	}
+
+	rp.player = player
+	rp.err = nil
+}
+
+func (rp *RetryPlayer) Mute(mute bool) error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.muted = mute
+	if rp.player == nil {
+		return nil
+	}
+
+	return rp.player.Mute(mute)
+}
+
+func (rp *RetryPlayer) Play(stream string) error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.stream = stream
+	if rp.player == nil {
+		return nil
+	}
+
+	return rp.player.Play(stream)
+}
+
+func (rp *RetryPlayer) Stop() error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.stream = ""
+	if rp.player == nil {
+		return nil
+	}
+
+	return rp.player.Stop()
+}
+
+func (rp *RetryPlayer) Reload() error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if rp.player == nil {
+		return nil
+	}
+
+	return rp.player.Reload()
+}
+
+func (rp *RetryPlayer) SetLabel(label Label) error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.label = label
+	if rp.player == nil {
+		return nil
+	}
+
+	return rp.player.SetLabel(label)
+}
+
+func (rp *RetryPlayer) Err() error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if rp.player == nil {
+		return rp.err
+	}
+
+	return rp.player.Err()
+}
+
+func (rp *RetryPlayer) Status() PlayerStatus {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if rp.player != nil {
+		return rp.player.Status()
+	}
+
+	if rp.err == errPlayerStarting {
+		return PlayerStatus{State: StateConnecting, Reason: rp.err.Error()}
+	}
+
+	return PlayerStatus{State: StateFailed, Reason: rp.err.Error()}
+}
+
+func (rp *RetryPlayer) Release() {
+	rp.cancel()
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if rp.player != nil {
+		rp.player.Release()
+		rp.player = nil
+	}
+}
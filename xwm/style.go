@@ -0,0 +1,13 @@
+package xwm
+
+// Style is how a Manager draws its root X window and the borders of windows in layout view.
+type Style struct {
+	Background  uint32
+	BorderWidth uint16
+	BorderColor uint32
+	// FocusBorderWidth is the border width of the focused window when it is wider than BorderWidth.
+	FocusBorderWidth uint16
+	FocusColor       uint32
+	// Label is the label of windows, the text is set by Window.SetLabel.
+	Label Label
+}
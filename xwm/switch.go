@@ -0,0 +1,230 @@
+package xwm
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+const (
+	// switchInterval is how often the hidden player is checked for its first frame.
+	switchInterval = 100 * time.Millisecond
+	// switchTimeout is how long the old stream is shown when the new stream does not start.
+	switchTimeout = 10 * time.Second
+)
+
+// SwitchPlayer plays streams in two X windows that are stacked on top of each other.
+// A new stream is played by a player that is created in the hidden X window and shown once it has a frame, so the old stream is shown until then.
+// The old player is released after the switch, so there is only a second player while switching.
+type SwitchPlayer struct {
+	q       *Queue
+	x       *xgb.Conn
+	name    string
+	factory PlayerFactory
+	wids    [2]xproto.Window
+
+	mu sync.Mutex
+	// players are nil when they are not playing
+	players [2]Player
+	active  int
+	streams [2]string
+	muted   bool
+	label   Label
+	// since is when the hidden player was told to play its stream
+	since time.Time
+	// gen cancels waiting for the hidden player
+	gen int
+}
+
+// NewSwitchPlayer creates players with the factory in the X windows, the first X window is shown.
+func NewSwitchPlayer(q *Queue, x *xgb.Conn, name string, wids [2]xproto.Window, factory PlayerFactory) *SwitchPlayer {
+	xproto.ConfigureWindow(x, wids[0], xproto.ConfigWindowStackMode, []uint32{xproto.StackModeAbove})
+
+	return &SwitchPlayer{
+		q:       q,
+		x:       x,
+		name:    name,
+		factory: factory,
+		wids:    wids,
+		players: [2]Player{NewRetryPlayer(name, wids[0], factory)},
+	}
+}
+
+func (sp *SwitchPlayer) Mute(mute bool) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.muted = mute
+
+	return sp.players[sp.active].Mute(mute)
+}
+
+func (sp *SwitchPlayer) Play(stream string) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.gen++
+	hidden := 1 - sp.active
+
+	// Shown player is already playing the stream
+	if sp.streams[sp.active] == stream {
+		sp.release(hidden)
+		return nil
+	}
+
+	// Nothing to keep showing
+	if sp.streams[sp.active] == "" {
+		sp.release(hidden)
+		sp.streams[sp.active] = stream
+		return sp.players[sp.active].Play(stream)
+	}
+
+	if sp.streams[hidden] != stream {
+		sp.release(hidden)
+
+		player := NewRetryPlayer(sp.name, sp.wids[hidden], sp.factory)
+		if err := player.Mute(true); err != nil {
+			player.Release()
+			return err
+		}
+		if err := player.SetLabel(sp.label); err != nil {
+			player.Release()
+			return err
+		}
+		if err := player.Play(stream); err != nil {
+			player.Release()
+			return err
+		}
+
+		sp.players[hidden] = player
+		sp.streams[hidden] = stream
+		sp.since = time.Now()
+	}
+
+	go sp.wait(sp.gen, hidden, sp.players[hidden], sp.since)
+
+	return nil
+}
+
+// wait shows the hidden player when its stream starts after since or after switchTimeout.
+func (sp *SwitchPlayer) wait(gen, hidden int, player Player, since time.Time) {
+	t := time.NewTicker(switchInterval)
+	defer t.Stop()
+	timeout := time.NewTimer(switchTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if started := player.Status().Started; started.IsZero() || started.Before(since) {
+				continue
+			}
+		case <-timeout.C:
+			log.Printf("xwm.SwitchPlayer.wait: %s: switching after %s without a frame", sp.name, switchTimeout)
+		}
+
+		// Restack inside HandleEvent like other X window changes
+		sp.q.Do(func() {
+			sp.mu.Lock()
+			defer sp.mu.Unlock()
+
+			if gen == sp.gen {
+				sp.show(hidden)
+			}
+		})
+
+		return
+	}
+}
+
+// show raises the hidden player and releases the player that was shown.
+func (sp *SwitchPlayer) show(hidden int) {
+	xproto.ConfigureWindow(sp.x, sp.wids[hidden], xproto.ConfigWindowStackMode, []uint32{xproto.StackModeAbove})
+
+	old := sp.active
+	sp.active = hidden
+	if err := sp.players[hidden].Mute(sp.muted); err != nil {
+		log.Printf("xwm.SwitchPlayer.show: %s: Mute: %s", sp.name, err)
+	}
+
+	sp.release(old)
+}
+
+// release releases the player when it is not shown.
+func (sp *SwitchPlayer) release(i int) {
+	if i == sp.active || sp.players[i] == nil {
+		return
+	}
+
+	sp.players[i].Release()
+	sp.players[i] = nil
+	sp.streams[i] = ""
+}
+
+func (sp *SwitchPlayer) Stop() error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.gen++
+	sp.release(1 - sp.active)
+	sp.streams[sp.active] = ""
+
+	return sp.players[sp.active].Stop()
+}
+
+func (sp *SwitchPlayer) Reload() error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	if hidden := sp.players[1-sp.active]; hidden != nil {
+		if err := hidden.Reload(); err != nil {
+			return err
+		}
+	}
+
+	return sp.players[sp.active].Reload()
+}
+
+func (sp *SwitchPlayer) SetLabel(label Label) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.label = label
+	for _, player := range sp.players {
+		if player == nil {
+			continue
+		}
+
+		if err := player.SetLabel(label); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sp *SwitchPlayer) Err() error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	return sp.players[sp.active].Err()
+}
+
+func (sp *SwitchPlayer) Status() PlayerStatus {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	return sp.players[sp.active].Status()
+}
+
+func (sp *SwitchPlayer) Release() {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.gen++
+	sp.release(1 - sp.active)
+	sp.players[sp.active].Release()
+}
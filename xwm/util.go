@@ -5,7 +5,8 @@ import (
 	"github.com/jezek/xgb/xproto"
 )
 
-func CreateXSubWindow(x *xgb.Conn, root xproto.Window) (xproto.Window, error) {
+// CreateXSubWindow creates and maps a X window in root with the style's border.
+func CreateXSubWindow(x *xgb.Conn, root xproto.Window, style Style) (xproto.Window, error) {
 	// Generate X window id
 	wid, err := xproto.NewWindowId(x)
 	if err != nil {
@@ -13,10 +14,10 @@ func CreateXSubWindow(x *xgb.Conn, root xproto.Window) (xproto.Window, error) {
 	}
 
 	// Create X window in root
-	if xproto.CreateWindowChecked(x, xproto.WindowClassCopyFromParent,
+	if err := xproto.CreateWindowChecked(x, xproto.WindowClassCopyFromParent,
 		wid, root,
-		0, 0, 1, 1, 0,
-		xproto.WindowClassInputOutput, xproto.WindowClassCopyFromParent, 0, []uint32{}).Check(); err != nil {
+		0, 0, 1, 1, style.BorderWidth,
+		xproto.WindowClassInputOutput, xproto.WindowClassCopyFromParent, xproto.CwBorderPixel, []uint32{style.BorderColor}).Check(); err != nil {
 		return 0, err
 	}
 
@@ -28,3 +29,28 @@ func CreateXSubWindow(x *xgb.Conn, root xproto.Window) (xproto.Window, error) {
 
 	return wid, nil
 }
+
+// CreateXChildWindow creates and maps a black X window in parent that covers it, events go to parent.
+func CreateXChildWindow(x *xgb.Conn, parent xproto.Window) (xproto.Window, error) {
+	// Generate X window id
+	wid, err := xproto.NewWindowId(x)
+	if err != nil {
+		return 0, err
+	}
+
+	// Create X window in parent
+	if err := xproto.CreateWindowChecked(x, xproto.WindowClassCopyFromParent,
+		wid, parent,
+		0, 0, 1, 1, 0,
+		xproto.WindowClassInputOutput, xproto.WindowClassCopyFromParent, xproto.CwBackPixel, []uint32{0}).Check(); err != nil {
+		return 0, err
+	}
+
+	// Show X window
+	if err := xproto.MapWindowChecked(x, wid).Check(); err != nil {
+		xproto.DestroyWindow(x, wid)
+		return 0, err
+	}
+
+	return wid, nil
+}
@@ -3,28 +3,42 @@ package xwm
 import (
 	"log"
 
+	"github.com/jezek/xgb"
 	"github.com/jezek/xgb/xproto"
 )
 
 type Window struct {
 	wid        xproto.Window
+	name       string
 	player     Player
 	mainStream string
 	subStream  string
 	background bool
+	// layers are X windows inside the window that are resized with it
+	layers []xproto.Window
 }
 
-func NewWindow(wid xproto.Window, player Player, mainStream, subStream string, background bool) Window {
+// NewWindow creates a window, layers are X windows inside wid that players draw to (e.g. for a SwitchPlayer).
+func NewWindow(wid xproto.Window, name string, player Player, mainStream, subStream string, background bool, layers ...xproto.Window) Window {
 	if subStream == "" {
 		subStream = mainStream
 	}
 
 	return Window{
 		wid:        wid,
+		name:       name,
 		player:     player,
 		mainStream: mainStream,
 		subStream:  subStream,
 		background: background,
+		layers:     layers,
+	}
+}
+
+// resize resizes the window's layers to the window's width and height.
+func (c Window) resize(x *xgb.Conn, width, height uint16) {
+	for _, layer := range c.layers {
+		xproto.ConfigureWindow(x, layer, xproto.ConfigWindowWidth|xproto.ConfigWindowHeight, []uint32{uint32(width), uint32(height)})
 	}
 }
 
@@ -44,6 +58,19 @@ func (c Window) Show(focus, fullscreen bool) {
 	}
 }
 
+// SetLabel shows the window's name and stream on top of the video.
+func (c Window) SetLabel(label Label, fullscreen bool) {
+	stream := "sub"
+	if fullscreen || c.subStream == c.mainStream {
+		stream = "main"
+	}
+	label.Text = c.name + "  " + stream
+
+	if err := c.player.SetLabel(label); err != nil {
+		log.Println("xwm.Window.SetLabel:", err)
+	}
+}
+
 func (c Window) Hide() {
 	if c.background {
 		if err := c.player.Play(c.subStream); err != nil {
@@ -62,6 +89,32 @@ func (c Window) Hide() {
 	}
 }
 
-func (c Window) Release() {
+func (c Window) Reload() {
+	if err := c.player.Reload(); err != nil {
+		log.Println("xwm.Window.Reload:", err)
+	}
+}
+
+// Err returns why the window's player is not working.
+func (c Window) Err() error {
+	return c.player.Err()
+}
+
+// Status returns the state of the window's stream.
+func (c Window) Status() PlayerStatus {
+	return c.player.Status()
+}
+
+func (c Window) WID() xproto.Window {
+	return c.wid
+}
+
+func (c Window) Name() string {
+	return c.name
+}
+
+// Release the player and destroy the X window.
+func (c Window) Release(x *xgb.Conn) {
 	c.player.Release()
+	xproto.DestroyWindow(x, c.wid)
 }